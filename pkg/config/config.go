@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
 	"github.com/suzuki-shunsuke/go-cliutil"
 	"go.yaml.in/yaml/v3"
 )
@@ -13,6 +14,7 @@ import (
 const configurationFileTemplate = `---
 # the configuration file of cmdx, which is a task runner.
 # https://github.com/suzuki-shunsuke/cmdx
+version: 1
 # timeout:
 #   duration: 600
 #   kill_after: 30
@@ -30,7 +32,6 @@ tasks:
   # - name: source
   #   short: s
   #   usage: source file path
-  #   description: source file path
   #   default: .drone.jsonnet
   #   required: true
   # - name: force
@@ -54,7 +55,10 @@ func New() *Client {
 	return &Client{}
 }
 
-func (client *Client) Read(cfgFilePath string, cfg any) error {
+// Read reads the configuration file.
+// The format is decided by the file extension. YAML, JSON, TOML, and Jsonnet are supported.
+// Unless `strict: false` is set, unknown keys are errors.
+func (client *Client) Read(cfgFilePath string, cfg *domain.Config) error {
 	f, err := os.Open(cfgFilePath)
 	if err != nil {
 		return fmt.Errorf("failed to open the configuration file %s: %w", cfgFilePath, err)
@@ -64,36 +68,90 @@ func (client *Client) Read(cfgFilePath string, cfg any) error {
 	if err != nil {
 		return fmt.Errorf("failed to read the configuration file %s: %w", cfgFilePath, err)
 	}
-	if err := yaml.Unmarshal(b, cfg); err != nil {
+	doc, err := parse(cfgFilePath, b)
+	if err != nil {
+		return fmt.Errorf("failed to parse the configuration file. the configuration file is invalid: %w", err)
+	}
+	if err := yaml.Unmarshal(doc.src, cfg); err != nil {
 		return fmt.Errorf("failed to parse the configuration file. the configuration file is invalid: %s: %w", cfgFilePath, err)
 	}
+	if cfg.Strict == nil || *cfg.Strict {
+		if err := checkUnknownKeys(cfgFilePath, doc); err != nil {
+			return fmt.Errorf("the configuration file has unknown keys. If you want to ignore unknown keys, please set 'strict: false'\n%w", err)
+		}
+	}
+	if len(doc.root.Content) != 0 {
+		setNodes(cfg.Tasks, doc.root.Content[0])
+	}
 	return nil
 }
 
+// setNodes sets YAML nodes to tasks so that the position of invalid settings can be outputted.
+func setNodes(tasks []domain.Task, node *yaml.Node) {
+	seq := mappingValue(node, "tasks")
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, n := range seq.Content {
+		if i >= len(tasks) {
+			return
+		}
+		tasks[i].Node = n
+		setNodes(tasks[i].Tasks, n)
+	}
+}
+
+// Create creates the configuration file with the default template.
 func (client *Client) Create(p string) error {
+	return client.CreateWithContent(p, []byte(configurationFileTemplate))
+}
+
+// CreateWithContent creates the configuration file.
+// If the configuration file already exists, do nothing.
+func (client *Client) CreateWithContent(p string, content []byte) error {
 	if _, err := os.Stat(p); err == nil {
 		// If the configuration file already exists, do nothing.
 		return nil
 	}
-	if err := os.WriteFile(p, []byte(configurationFileTemplate), 0o644); err != nil { //nolint:gosec,mnd
+	if err := CheckCreatable(p); err != nil {
+		return err
+	}
+	if err := os.WriteFile(p, content, 0o644); err != nil { //nolint:gosec,mnd
 		return fmt.Errorf("failed to create the configuration file %s: %w", p, err)
 	}
 	return nil
 }
 
-func (client *Client) GetFilePath(cfgFileName string) (string, error) {
-	names := []string{".cmdx.yaml", ".cmdx.yml", "cmdx.yaml", "cmdx.yml"}
+// CheckCreatable returns an error if cmdx can't create the configuration file.
+// Templates of the configuration file are YAML, so only YAML configuration files can be created.
+func CheckCreatable(p string) error {
+	if format(p) != formatYAML {
+		return fmt.Errorf("only YAML configuration files can be created. Please use the extension .yaml or .yml: %s", p)
+	}
+	return nil
+}
+
+func fileNames(cfgFileName string) []string {
 	if cfgFileName != "" {
-		names = []string{cfgFileName}
+		return []string{cfgFileName}
+	}
+	return []string{
+		".cmdx.yaml", ".cmdx.yml", ".cmdx.json", ".cmdx.toml", ".cmdx.jsonnet",
+		"cmdx.yaml", "cmdx.yml", "cmdx.json", "cmdx.toml", "cmdx.jsonnet",
 	}
+}
+
+func existFile(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
+
+func (client *Client) GetFilePath(cfgFileName string) (string, error) {
 	wd, err := os.Getwd()
 	if err != nil {
 		return "", fmt.Errorf("failed to get the current directory path: %w", err)
 	}
-	p, err := cliutil.FindFile(wd, func(name string) bool {
-		_, err := os.Stat(name)
-		return err == nil
-	}, names...)
+	p, err := cliutil.FindFile(wd, existFile, fileNames(cfgFileName)...)
 	if err == nil {
 		return p, nil
 	}
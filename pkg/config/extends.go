@@ -0,0 +1,202 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"go.yaml.in/yaml/v3"
+)
+
+// ResolveExtends merges tasks specified by `extends` into tasks which extend them.
+// A task can extend only a sibling task, which can extend another task.
+func ResolveExtends(tasks []domain.Task) error {
+	indexes := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		indexes[task.Name] = i
+	}
+	resolved := make(map[int]struct{}, len(tasks))
+	var resolve func(i int, path []string) error
+	resolve = func(i int, path []string) error {
+		task := &tasks[i]
+		if _, ok := resolved[i]; ok || task.Extends == "" {
+			return nil
+		}
+		path = append(path, task.Name)
+		if slices.Contains(path[:len(path)-1], task.Name) {
+			start := slices.Index(path, task.Name)
+			return extendsError(task, "the task extends circularly: "+strings.Join(path[start:], " -> "))
+		}
+		j, ok := indexes[task.Extends]
+		if !ok {
+			return extendsError(task, fmt.Sprintf(`the task to extend isn't found: task: "%s", extends: "%s"`, task.Name, task.Extends))
+		}
+		if err := resolve(j, path); err != nil {
+			return err
+		}
+		extendTask(task, &tasks[j])
+		resolved[i] = struct{}{}
+		return nil
+	}
+	for i := range tasks {
+		if err := resolve(i, nil); err != nil {
+			return err
+		}
+	}
+	for i := range tasks {
+		if err := ResolveExtends(tasks[i].Tasks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extendsError returns the error with the position of `extends` of the task.
+func extendsError(task *domain.Task, msg string) error {
+	if task.Node != nil {
+		if node := mappingValue(task.Node, "extends"); node != nil && node.Line != 0 {
+			return errors.New(domain.Position(task.Source, node.Line, node.Column) + ": " + msg)
+		}
+	}
+	if task.Source != "" {
+		return errors.New(task.Source + ": " + msg)
+	}
+	return errors.New(msg)
+}
+
+// extendTask merges base into task.
+// Flags and positional arguments are merged by name, and the task's ones take precedence.
+// Environment variables are merged, and the task's ones take precedence.
+// Requirements are appended.
+// Timeout, shell, and script are inherited if the task doesn't set them.
+// If the task sets neither script nor steps, base's steps are also inherited.
+func extendTask(task, base *domain.Task) {
+	flags, flagOrigins := mergeByName(base.Flags, task.Flags, func(flag domain.Flag) string {
+		return flag.Name
+	})
+	args, argOrigins := mergeByName(base.Args, task.Args, func(arg domain.Arg) string {
+		return arg.Name
+	})
+	task.Node = mergeNodes(task, base, map[string][]origin{
+		"flags": flagOrigins,
+		"args":  argOrigins,
+	})
+	task.Flags = flags
+	task.Args = args
+
+	if len(base.Environment) != 0 {
+		env := make(map[string]string, len(base.Environment)+len(task.Environment))
+		for k, v := range base.Environment {
+			env[k] = v
+		}
+		for k, v := range task.Environment {
+			env[k] = v
+		}
+		task.Environment = env
+	}
+
+	task.Require.Exec = append(slices.Clone(base.Require.Exec), task.Require.Exec...)
+	task.Require.Environment = append(slices.Clone(base.Require.Environment), task.Require.Environment...)
+
+	if task.Timeout.Duration == 0 {
+		task.Timeout.Duration = base.Timeout.Duration
+	}
+	if task.Timeout.KillAfter == 0 {
+		task.Timeout.KillAfter = base.Timeout.KillAfter
+	}
+	if len(task.Shell) == 0 {
+		task.Shell = base.Shell
+	}
+	if task.Script == "" && len(task.Steps) == 0 {
+		task.Script = base.Script
+		task.Steps = base.Steps
+	}
+}
+
+// origin is the index of the merged element in the original list.
+// If base is true, the element comes from the base task.
+type origin struct {
+	base  bool
+	index int
+}
+
+// mergeByName returns base whose elements are overridden by elements of list with the same names.
+// Elements of list whose names aren't in base are appended.
+// It also returns where each merged element comes from.
+func mergeByName[T any](base, list []T, name func(T) string) ([]T, []origin) {
+	merged := slices.Clone(base)
+	origins := make([]origin, len(base), len(base)+len(list))
+	for i := range base {
+		origins[i] = origin{base: true, index: i}
+	}
+	for j, elem := range list {
+		if i := slices.IndexFunc(merged, func(e T) bool {
+			return name(e) == name(elem)
+		}); i != -1 {
+			merged[i] = elem
+			origins[i] = origin{index: j}
+			continue
+		}
+		merged = append(merged, elem)
+		origins = append(origins, origin{index: j})
+	}
+	return merged, origins
+}
+
+// mergeNodes returns the node of the task whose sequences of the keys are rearranged in the order of merged elements,
+// so that positions of invalid settings are found by indexes of merged elements.
+// Elements of the base task point to the base task's nodes if the base task is defined in the same file.
+// Otherwise, they point to `extends` of the task.
+func mergeNodes(task, base *domain.Task, keys map[string][]origin) *yaml.Node {
+	if task.Node == nil || task.Node.Kind != yaml.MappingNode {
+		return task.Node
+	}
+	node := *task.Node
+	node.Content = slices.Clone(task.Node.Content)
+	extends := mappingValue(task.Node, "extends")
+	for key, origins := range keys {
+		if len(origins) == 0 {
+			continue
+		}
+		own := mappingValue(task.Node, key)
+		var baseSeq *yaml.Node
+		if base.Node != nil && base.Source == task.Source {
+			baseSeq = mappingValue(base.Node, key)
+		}
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if own != nil {
+			seq.Line, seq.Column = own.Line, own.Column
+		} else if extends != nil {
+			seq.Line, seq.Column = extends.Line, extends.Column
+		}
+		for _, o := range origins {
+			src := own
+			if o.base {
+				src = baseSeq
+			}
+			elem := extends
+			if src != nil && src.Kind == yaml.SequenceNode && o.index < len(src.Content) {
+				elem = src.Content[o.index]
+			}
+			if elem == nil {
+				elem = task.Node
+			}
+			seq.Content = append(seq.Content, elem)
+		}
+		setMappingValue(&node, key, seq)
+	}
+	return &node
+}
+
+// setMappingValue sets the value of the key in the mapping node.
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
@@ -0,0 +1,191 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/validate"
+)
+
+func TestResolveExtends(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		tasks []domain.Task
+		exp   []domain.Task
+		isErr bool
+	}{
+		{
+			title: "extend the task extending another task",
+			tasks: []domain.Task{
+				{
+					Name:    "deploy-prod",
+					Extends: "deploy",
+					Flags: []domain.Flag{
+						{Name: "region", Default: "us-east-1"},
+						{Name: "force", Type: "bool"},
+					},
+					Environment: map[string]string{"STAGE": "prod"},
+				},
+				{
+					Name:    "deploy",
+					Extends: "base",
+					Flags: []domain.Flag{
+						{Name: "region", Default: "ap-northeast-1"},
+						{Name: "version"},
+					},
+					Environment: map[string]string{"STAGE": "dev", "APP": "api"},
+					Script:      "deploy {{.version}}",
+				},
+				{
+					Name:    "base",
+					Shell:   []string{"sh", "-c"},
+					Timeout: domain.Timeout{Duration: 60},
+					Require: domain.Require{Exec: []domain.StrList{{"aws"}}},
+				},
+			},
+			exp: []domain.Task{
+				{
+					Name:    "deploy-prod",
+					Extends: "deploy",
+					Flags: []domain.Flag{
+						{Name: "region", Default: "us-east-1"},
+						{Name: "version"},
+						{Name: "force", Type: "bool"},
+					},
+					Environment: map[string]string{"STAGE": "prod", "APP": "api"},
+					Script:      "deploy {{.version}}",
+					Shell:       []string{"sh", "-c"},
+					Timeout:     domain.Timeout{Duration: 60},
+					Require:     domain.Require{Exec: []domain.StrList{{"aws"}}},
+				},
+				{
+					Name:    "deploy",
+					Extends: "base",
+					Flags: []domain.Flag{
+						{Name: "region", Default: "ap-northeast-1"},
+						{Name: "version"},
+					},
+					Environment: map[string]string{"STAGE": "dev", "APP": "api"},
+					Script:      "deploy {{.version}}",
+					Shell:       []string{"sh", "-c"},
+					Timeout:     domain.Timeout{Duration: 60},
+					Require:     domain.Require{Exec: []domain.StrList{{"aws"}}},
+				},
+				{
+					Name:    "base",
+					Shell:   []string{"sh", "-c"},
+					Timeout: domain.Timeout{Duration: 60},
+					Require: domain.Require{Exec: []domain.StrList{{"aws"}}},
+				},
+			},
+		},
+		{
+			title: "not found",
+			tasks: []domain.Task{
+				{Name: "deploy", Extends: "base"},
+			},
+			isErr: true,
+		},
+		{
+			title: "circular",
+			tasks: []domain.Task{
+				{Name: "a", Extends: "b"},
+				{Name: "b", Extends: "a"},
+			},
+			isErr: true,
+		},
+		{
+			title: "sub tasks can't extend tasks of other levels",
+			tasks: []domain.Task{
+				{Name: "base", Script: "echo base"},
+				{
+					Name: "admin",
+					Tasks: []domain.Task{
+						{Name: "deploy", Extends: "base"},
+					},
+				},
+			},
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			err := ResolveExtends(d.tasks)
+			if d.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, d.tasks)
+		})
+	}
+}
+
+func TestResolveExtends_positions(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		cfg   string
+		exp   string
+	}{
+		{
+			title: "invalid flags of the task and the base task",
+			cfg: `tasks:
+- name: deploy
+  flags:
+  - name: region
+    type: int
+  script: echo deploy
+- name: deploy-prod
+  extends: deploy
+  flags:
+  - name: force
+    type: foo
+`,
+			exp: `.cmdx.yaml:5:11: the flag type should be either '' or 'string' or 'bool'. task: deploy, flag: region, flag.type: int
+.cmdx.yaml:5:11: the flag type should be either '' or 'string' or 'bool'. task: deploy-prod, flag: region, flag.type: int
+.cmdx.yaml:11:11: the flag type should be either '' or 'string' or 'bool'. task: deploy-prod, flag: force, flag.type: foo`,
+		},
+		{
+			title: "the base task isn't found",
+			cfg: `tasks:
+- name: deploy-prod
+  extends: deploy
+`,
+			exp: `.cmdx.yaml:3:12: the task to extend isn't found: task: "deploy-prod", extends: "deploy"`,
+		},
+		{
+			title: "circular",
+			cfg: `tasks:
+- name: a
+  extends: b
+- name: b
+  extends: a
+`,
+			exp: `.cmdx.yaml:3:12: the task extends circularly: a -> b -> a`,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{".cmdx.yaml": d.cfg})
+			cfg := &domain.Config{}
+			require.NoError(t, New().Load(filepath.Join(dir, ".cmdx.yaml"), cfg))
+			for i := range cfg.Tasks {
+				cfg.Tasks[i].Source = ".cmdx.yaml"
+			}
+			err := ResolveExtends(cfg.Tasks)
+			if err == nil {
+				err = validate.Config(cfg)
+			}
+			require.Error(t, err)
+			assert.Equal(t, d.exp, err.Error())
+		})
+	}
+}
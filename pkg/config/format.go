@@ -0,0 +1,111 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/google/go-jsonnet"
+	"go.yaml.in/yaml/v3"
+)
+
+const (
+	formatYAML    = "yaml"
+	formatJSON    = "json"
+	formatTOML    = "toml"
+	formatJsonnet = "jsonnet"
+)
+
+// format returns the format of the configuration file by the file extension.
+// Files with unknown extensions are treated as YAML.
+func format(cfgFilePath string) string {
+	switch filepath.Ext(cfgFilePath) {
+	case ".json":
+		return formatJSON
+	case ".toml":
+		return formatTOML
+	case ".jsonnet":
+		return formatJsonnet
+	default:
+		return formatYAML
+	}
+}
+
+// document is a parsed configuration file.
+// src is YAML which is decoded into domain.Config.
+// root is the YAML document node whose positions refer to the original file.
+// If the positions are unknown, they are zero.
+type document struct {
+	src  []byte
+	root *yaml.Node
+}
+
+// parse converts the content of the configuration file into YAML.
+// Errors include the file path and the position if it is known.
+// JSON is a subset of YAML, so JSON is parsed as YAML and the positions are kept.
+// TOML is converted into the YAML node with the positions in the TOML file.
+// Jsonnet is evaluated into JSON, and the positions refer to the evaluated JSON.
+func parse(cfgFilePath string, b []byte) (*document, error) {
+	switch format(cfgFilePath) {
+	case formatJSON:
+		if err := validJSON(cfgFilePath, b); err != nil {
+			return nil, err
+		}
+		return parseYAML(cfgFilePath, b)
+	case formatTOML:
+		root, err := parseTOML(cfgFilePath, b)
+		if err != nil {
+			return nil, err
+		}
+		src, err := yaml.Marshal(root)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to convert TOML into YAML: %w", cfgFilePath, err)
+		}
+		return &document{src: src, root: root}, nil
+	case formatJsonnet:
+		vm := jsonnet.MakeVM()
+		// Imports are relative to the configuration file.
+		vm.Importer(&jsonnet.FileImporter{
+			JPaths: []string{filepath.Dir(cfgFilePath)},
+		})
+		s, err := vm.EvaluateAnonymousSnippet(cfgFilePath, string(b))
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to evaluate Jsonnet: %w", cfgFilePath, err)
+		}
+		return parseYAML(cfgFilePath, []byte(s))
+	default:
+		return parseYAML(cfgFilePath, b)
+	}
+}
+
+func parseYAML(cfgFilePath string, b []byte) (*document, error) {
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal(b, root); err != nil {
+		return nil, fmt.Errorf("%s: %w", cfgFilePath, err)
+	}
+	return &document{src: b, root: root}, nil
+}
+
+// validJSON returns an error with the position if b isn't valid JSON.
+func validJSON(cfgFilePath string, b []byte) error {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			// Offset is the position after the invalid character.
+			line, column := position(b, int(syntaxErr.Offset)-1)
+			return fmt.Errorf("%s:%d:%d: %w", cfgFilePath, line, column, err)
+		}
+		return fmt.Errorf("%s: %w", cfgFilePath, err)
+	}
+	return nil
+}
+
+// position converts the byte offset into the line and column.
+func position(b []byte, offset int) (int, int) {
+	b = b[:max(min(offset, len(b)), 0)]
+	line := bytes.Count(b, []byte("\n")) + 1
+	return line, len(b) - bytes.LastIndexByte(b, '\n')
+}
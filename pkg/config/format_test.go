@@ -0,0 +1,188 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+func TestClient_Read(t *testing.T) {
+	t.Parallel()
+	exp := &domain.Config{
+		Environment: map[string]string{"FOO": "foo"},
+		Timeout:     domain.Timeout{Duration: 60},
+		Tasks: []domain.Task{
+			{
+				Name:   "build",
+				Flags:  []domain.Flag{{Name: "os", Default: "linux"}},
+				Script: "go build",
+				Vars:   map[string]domain.Var{"sha": {Sh: "git rev-parse HEAD"}},
+			},
+			{
+				Name: "admin",
+				Tasks: []domain.Task{
+					{Name: "create", Script: "create", Timeout: domain.Timeout{Duration: 10}},
+				},
+			},
+		},
+	}
+	data := []struct {
+		title string
+		files map[string]string
+		exp   *domain.Config
+		// pos is the expected position of the second task.
+		pos   [2]int
+		isErr string
+	}{
+		{
+			title: "json",
+			files: map[string]string{
+				".cmdx.json": `{
+	"environment": {"FOO": "foo"},
+	"timeout": {"duration": 60},
+	"tasks": [
+		{
+			"name": "build",
+			"flags": [{"name": "os", "default": "linux"}],
+			"script": "go build",
+			"vars": {"sha": {"sh": "git rev-parse HEAD"}}
+		},
+		{
+			"name": "admin",
+			"tasks": [{"name": "create", "script": "create", "timeout": {"duration": 10}}]
+		}
+	]
+}
+`,
+			},
+			exp: exp,
+			pos: [2]int{11, 3},
+		},
+		{
+			title: "toml",
+			files: map[string]string{
+				".cmdx.toml": `timeout.duration = 60
+
+[environment]
+FOO = "foo"
+
+[[tasks]]
+name = "build"
+flags = [{ name = "os", default = "linux" }]
+script = "go build"
+vars = { sha = { sh = "git rev-parse HEAD" } }
+
+[[tasks]]
+name = "admin"
+
+[[tasks.tasks]]
+name = "create"
+script = "create"
+
+[tasks.tasks.timeout]
+duration = 10
+`,
+			},
+			exp: exp,
+			pos: [2]int{12, 3},
+		},
+		{
+			title: "jsonnet",
+			files: map[string]string{
+				".cmdx.jsonnet": `local lib = import 'lib.libsonnet';
+{
+  environment: { FOO: 'foo' },
+  timeout: { duration: 60 },
+  tasks: [
+    lib.build('os'),
+    { name: 'admin', tasks: [{ name: 'create', script: 'create', timeout: { duration: 10 } }] },
+  ],
+}
+`,
+				"lib.libsonnet": `{
+  build(flag):: {
+    name: 'build',
+    flags: [{ name: flag, default: 'linux' }],
+    script: 'go build',
+    vars: { sha: { sh: 'git rev-parse HEAD' } },
+  },
+}
+`,
+			},
+			exp: exp,
+		},
+		{
+			title: "invalid json",
+			files: map[string]string{
+				".cmdx.json": `{
+  "tasks": [,]
+}`,
+			},
+			isErr: ".cmdx.json:2:13: invalid character ',' looking for beginning of value",
+		},
+		{
+			title: "invalid toml",
+			files: map[string]string{
+				".cmdx.toml": `[[tasks]]
+name = "a"
+name = "b"
+`,
+			},
+			isErr: `.cmdx.toml:3:1: the key "name" is defined twice`,
+		},
+		{
+			title: "unknown key in toml",
+			files: map[string]string{
+				".cmdx.toml": `[[tasks]]
+name = "a"
+scrpt = "b"
+`,
+			},
+			isErr: `.cmdx.toml:3:1: unknown key "scrpt"`,
+		},
+		{
+			title: "unknown key in jsonnet",
+			files: map[string]string{
+				".cmdx.jsonnet": `{tasks: [{name: 'a', scrpt: 'b'}]}`,
+			},
+			isErr: `.cmdx.jsonnet (evaluated JSON):5:10: unknown key "scrpt"`,
+		},
+	}
+	client := New()
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			writeFiles(t, dir, d.files)
+			var p string
+			for name := range d.files {
+				if filepath.Ext(name) != ".libsonnet" {
+					p = filepath.Join(dir, name)
+				}
+			}
+			cfg := &domain.Config{}
+			err := client.Read(p, cfg)
+			if d.isErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), d.isErr)
+				return
+			}
+			require.NoError(t, err)
+			if d.pos != [2]int{} {
+				node := cfg.Tasks[1].Node
+				require.NotNil(t, node)
+				assert.Equal(t, d.pos, [2]int{node.Line, node.Column})
+			}
+			for i := range cfg.Tasks {
+				cfg.Tasks[i].Node = nil
+				for j := range cfg.Tasks[i].Tasks {
+					cfg.Tasks[i].Tasks[j].Node = nil
+				}
+			}
+			assert.Equal(t, d.exp, cfg)
+		})
+	}
+}
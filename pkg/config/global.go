@@ -0,0 +1,138 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+// GetGlobalFilePath returns the path of the user level configuration file.
+// The path is $XDG_CONFIG_HOME/cmdx/config.yaml, and $XDG_CONFIG_HOME defaults to ~/.config.
+// If the file doesn't exist, it returns an empty string.
+func (client *Client) GetGlobalFilePath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			// If the home directory is unknown, the global configuration file isn't used.
+			return "", nil //nolint:nilerr
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	p := filepath.Join(dir, "cmdx", "config.yaml")
+	if _, err := os.Stat(p); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to check if the global configuration file exists: %w", err)
+	}
+	return p, nil
+}
+
+// LoadGlobal loads the user level configuration file.
+// Merge ignores env_files of the user level configuration, so they are set to its tasks.
+// Tasks are run in the project directory, so relative paths of env_files are converted into absolute paths
+// which are relative to the directory of the user level configuration file.
+func (client *Client) LoadGlobal(p string, cfg *domain.Config) error {
+	if err := client.Load(p, cfg); err != nil {
+		return err
+	}
+	dir := filepath.Dir(p)
+	envFiles := absEnvFiles(dir, cfg.EnvFiles)
+	for i, task := range cfg.Tasks {
+		cfg.Tasks[i].EnvFiles = append(slices.Clone(envFiles), absEnvFiles(dir, task.EnvFiles)...)
+	}
+	return nil
+}
+
+// absEnvFiles returns env_files whose relative paths are joined with dir.
+func absEnvFiles(dir string, files []domain.EnvFile) []domain.EnvFile {
+	if len(files) == 0 {
+		return nil
+	}
+	arr := make([]domain.EnvFile, len(files))
+	for i, file := range files {
+		if !filepath.IsAbs(file.Path) {
+			file.Path = filepath.Join(dir, file.Path)
+		}
+		arr[i] = file
+	}
+	return arr
+}
+
+// Merge merges the user level configuration base into the project configuration cfg.
+// Settings of cfg take precedence over base.
+// Tasks of base are appended unless cfg has tasks with the same names.
+// Hooks of base are ignored.
+func Merge(cfg, base *domain.Config) {
+	names := make(map[string]struct{}, len(cfg.Tasks))
+	shortNames := make(map[string]struct{}, len(cfg.Tasks))
+	for _, task := range cfg.Tasks {
+		names[task.Name] = struct{}{}
+		if task.Short != "" {
+			shortNames[task.Short] = struct{}{}
+		}
+	}
+	for _, task := range base.Tasks {
+		if _, ok := names[task.Name]; ok {
+			continue
+		}
+		if _, ok := shortNames[task.Short]; ok {
+			task.Short = ""
+		}
+		cfg.Tasks = append(cfg.Tasks, task)
+	}
+
+	if len(base.Environment) != 0 {
+		if cfg.Environment == nil {
+			cfg.Environment = make(map[string]string, len(base.Environment))
+		}
+		for k, v := range base.Environment {
+			if _, ok := cfg.Environment[k]; !ok {
+				cfg.Environment[k] = v
+			}
+		}
+	}
+	if len(base.Vars) != 0 {
+		if cfg.Vars == nil {
+			cfg.Vars = make(map[string]domain.Var, len(base.Vars))
+		}
+		for k, v := range base.Vars {
+			if _, ok := cfg.Vars[k]; !ok {
+				cfg.Vars[k] = v
+			}
+		}
+	}
+	for k, v := range base.Profiles {
+		if _, ok := cfg.Profiles[k]; ok {
+			continue
+		}
+		if cfg.Profiles == nil {
+			cfg.Profiles = make(map[string]domain.Profile, len(base.Profiles))
+		}
+		cfg.Profiles[k] = v
+	}
+	if len(cfg.InputEnvs) == 0 {
+		cfg.InputEnvs = base.InputEnvs
+	}
+	if len(cfg.ScriptEnvs) == 0 {
+		cfg.ScriptEnvs = base.ScriptEnvs
+	}
+	if cfg.Timeout.Duration == 0 {
+		cfg.Timeout.Duration = base.Timeout.Duration
+	}
+	if cfg.Timeout.KillAfter == 0 {
+		cfg.Timeout.KillAfter = base.Timeout.KillAfter
+	}
+	if cfg.Quiet == nil {
+		cfg.Quiet = base.Quiet
+	}
+	if cfg.Concurrency == 0 {
+		cfg.Concurrency = base.Concurrency
+	}
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+	quiet := true
+	data := []struct {
+		title string
+		cfg   *domain.Config
+		base  *domain.Config
+		exp   *domain.Config
+	}{
+		{
+			title: "project settings take precedence",
+			cfg: &domain.Config{
+				Tasks: []domain.Task{
+					{Name: "build", Short: "b", Script: "make"},
+				},
+				Environment: map[string]string{"FOO": "project"},
+				Vars:        map[string]domain.Var{"version": {Value: "v1"}},
+				Timeout:     domain.Timeout{Duration: 60},
+			},
+			base: &domain.Config{
+				Tasks: []domain.Task{
+					{Name: "build", Script: "go build"},
+					{Name: "browse", Short: "b", Script: "gh browse"},
+					{Name: "logs", Script: "tail -f log"},
+				},
+				Environment: map[string]string{"FOO": "global", "BAR": "global"},
+				Vars:        map[string]domain.Var{"version": {Value: "v2"}, "sha": {Sh: "git rev-parse HEAD"}},
+				Timeout:     domain.Timeout{Duration: 30, KillAfter: 10},
+				Quiet:       &quiet,
+				Concurrency: 4,
+				Hooks:       domain.Hooks{Before: "echo before"},
+			},
+			exp: &domain.Config{
+				Tasks: []domain.Task{
+					{Name: "build", Short: "b", Script: "make"},
+					{Name: "browse", Script: "gh browse"},
+					{Name: "logs", Script: "tail -f log"},
+				},
+				Environment: map[string]string{"FOO": "project", "BAR": "global"},
+				Vars:        map[string]domain.Var{"version": {Value: "v1"}, "sha": {Sh: "git rev-parse HEAD"}},
+				Timeout:     domain.Timeout{Duration: 60, KillAfter: 10},
+				Quiet:       &quiet,
+				Concurrency: 4,
+			},
+		},
+		{
+			title: "empty base",
+			cfg: &domain.Config{
+				Tasks: []domain.Task{
+					{Name: "build", Script: "make"},
+				},
+			},
+			base: &domain.Config{},
+			exp: &domain.Config{
+				Tasks: []domain.Task{
+					{Name: "build", Script: "make"},
+				},
+			},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			Merge(d.cfg, d.base)
+			assert.Equal(t, d.exp, d.cfg)
+		})
+	}
+}
+
+func TestClient_LoadGlobal(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"cmdx/config.yaml": `
+env_files:
+- .env
+tasks:
+- name: token
+  env_files:
+  - path: /etc/cmdx.env
+    optional: true
+  - .env.token
+  script: echo $TOKEN
+- name: hello
+  script: echo hello
+`,
+	})
+	cfgDir := filepath.Join(dir, "cmdx")
+	cfg := &domain.Config{}
+	require.NoError(t, New().LoadGlobal(filepath.Join(cfgDir, "config.yaml"), cfg))
+	assert.Equal(t, []domain.EnvFile{
+		{Path: filepath.Join(cfgDir, ".env")},
+		{Path: "/etc/cmdx.env", Optional: true},
+		{Path: filepath.Join(cfgDir, ".env.token")},
+	}, cfg.Tasks[0].EnvFiles)
+	assert.Equal(t, []domain.EnvFile{
+		{Path: filepath.Join(cfgDir, ".env")},
+	}, cfg.Tasks[1].EnvFiles)
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+// Load reads the configuration file and files included by `includes`.
+// Tasks of included files are appended to cfg.Tasks, and the path of the file is set to task.Source.
+func (client *Client) Load(cfgFilePath string, cfg *domain.Config) error {
+	if err := client.Read(cfgFilePath, cfg); err != nil {
+		return err
+	}
+	if err := CheckVersion(cfgFilePath, cfg.Version); err != nil {
+		return err
+	}
+	setSource(cfg.Tasks, cfgFilePath)
+	abs, err := filepath.Abs(cfgFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to get the absolute path of the configuration file %s: %w", cfgFilePath, err)
+	}
+	tasks, err := client.include(cfgFilePath, cfg.Includes, map[string]struct{}{abs: {}})
+	if err != nil {
+		return err
+	}
+	cfg.Tasks = append(cfg.Tasks, tasks...)
+	return nil
+}
+
+func setSource(tasks []domain.Task, p string) {
+	for i := range tasks {
+		tasks[i].Source = p
+	}
+}
+
+// include reads included files and returns their tasks.
+// visiting is the set of absolute paths of files which are being read, which is used to detect circular includes.
+func (client *Client) include(cfgFilePath string, includes []domain.Include, visiting map[string]struct{}) ([]domain.Task, error) {
+	tasks := []domain.Task{}
+	for i, inc := range includes {
+		if inc.Path == "" {
+			return nil, fmt.Errorf("includes[%d].path is required: %s", i, cfgFilePath)
+		}
+		pattern := inc.Path
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(cfgFilePath), pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("the included path is invalid: %s: %w", inc.Path, err)
+		}
+		if len(matches) == 0 && !hasGlobMeta(inc.Path) {
+			return nil, fmt.Errorf("the included file isn't found: %s: included from %s", inc.Path, cfgFilePath)
+		}
+		for _, p := range matches {
+			ts, err := client.includeFile(p, visiting)
+			if err != nil {
+				return nil, err
+			}
+			if inc.Namespace != "" {
+				namespaceTasks(ts, inc.Namespace)
+			}
+			tasks = append(tasks, ts...)
+		}
+	}
+	return tasks, nil
+}
+
+func (client *Client) includeFile(p string, visiting map[string]struct{}) ([]domain.Task, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the absolute path of the included file %s: %w", p, err)
+	}
+	if _, ok := visiting[abs]; ok {
+		return nil, errors.New("the configuration file is included circularly: " + p)
+	}
+	visiting[abs] = struct{}{}
+	defer delete(visiting, abs)
+
+	cfg := &domain.Config{}
+	if err := client.Read(p, cfg); err != nil {
+		return nil, err
+	}
+	if err := CheckVersion(p, cfg.Version); err != nil {
+		return nil, err
+	}
+	setSource(cfg.Tasks, p)
+	tasks, err := client.include(p, cfg.Includes, visiting)
+	if err != nil {
+		return nil, err
+	}
+	return append(cfg.Tasks, tasks...), nil
+}
+
+func hasGlobMeta(p string) bool {
+	return strings.ContainsAny(p, `*?[\`)
+}
+
+// namespaceTasks prefixes names of tasks with the namespace.
+// Dependencies and step tasks which refer tasks in the same file are also prefixed.
+func namespaceTasks(tasks []domain.Task, namespace string) {
+	names := make(map[string]struct{}, len(tasks))
+	for _, task := range tasks {
+		names[task.Name] = struct{}{}
+	}
+	for i := range tasks {
+		task := &tasks[i]
+		task.Name = namespace + ":" + task.Name
+		if task.Short != "" {
+			task.Short = namespace + ":" + task.Short
+		}
+		if task.Extends != "" {
+			task.Extends = namespaceReference(task.Extends, names, namespace)
+		}
+		namespaceReferences(task, names, namespace)
+	}
+}
+
+func namespaceReferences(task *domain.Task, names map[string]struct{}, namespace string) {
+	for i, dep := range task.DependsOn {
+		task.DependsOn[i] = namespaceReference(dep, names, namespace)
+	}
+	for i, step := range task.Steps {
+		if step.Task != "" {
+			task.Steps[i].Task = namespaceReference(step.Task, names, namespace)
+		}
+	}
+	for i := range task.Tasks {
+		namespaceReferences(&task.Tasks[i], names, namespace)
+	}
+}
+
+func namespaceReference(ref string, names map[string]struct{}, namespace string) string {
+	fields := strings.Fields(ref)
+	if len(fields) == 0 {
+		return ref
+	}
+	if _, ok := names[fields[0]]; !ok {
+		return ref
+	}
+	return namespace + ":" + strings.Join(fields, " ")
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+}
+
+func TestClient_Load(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		files map[string]string
+		exp   []domain.Task
+		isErr bool
+	}{
+		{
+			title: "glob and namespace",
+			files: map[string]string{
+				".cmdx.yaml": `
+includes:
+- path: .cmdx.d/*.yaml
+  namespace: db
+tasks:
+- name: build
+  script: make
+`,
+				".cmdx.d/migrate.yaml": `
+tasks:
+- name: migrate
+  short: m
+  depends_on: [setup, build]
+  script: migrate
+- name: setup
+  script: setup
+`,
+			},
+			exp: []domain.Task{
+				{Name: "build", Script: "make", Source: ".cmdx.yaml"},
+				{Name: "db:migrate", Short: "db:m", DependsOn: []string{"db:setup", "build"}, Script: "migrate", Source: ".cmdx.d/migrate.yaml"},
+				{Name: "db:setup", Script: "setup", Source: ".cmdx.d/migrate.yaml"},
+			},
+		},
+		{
+			title: "nested includes",
+			files: map[string]string{
+				".cmdx.yaml": `
+includes:
+- path: a/a.yaml
+`,
+				"a/a.yaml": `
+includes:
+- path: b.yaml
+  namespace: b
+`,
+				"a/b.yaml": `
+tasks:
+- name: hello
+  script: echo hello
+`,
+			},
+			exp: []domain.Task{
+				{Name: "b:hello", Script: "echo hello", Source: "a/b.yaml"},
+			},
+		},
+		{
+			title: "file isn't found",
+			files: map[string]string{
+				".cmdx.yaml": `
+includes:
+- path: foo.yaml
+`,
+			},
+			isErr: true,
+		},
+		{
+			title: "circular include",
+			files: map[string]string{
+				".cmdx.yaml": `
+includes:
+- path: a.yaml
+`,
+				"a.yaml": `
+includes:
+- path: .cmdx.yaml
+`,
+			},
+			isErr: true,
+		},
+	}
+	client := New()
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			writeFiles(t, dir, d.files)
+			cfg := &domain.Config{}
+			err := client.Load(filepath.Join(dir, ".cmdx.yaml"), cfg)
+			if d.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for i := range cfg.Tasks {
+				rel, err := filepath.Rel(dir, cfg.Tasks[i].Source)
+				require.NoError(t, err)
+				cfg.Tasks[i].Source = rel
+				cfg.Tasks[i].Node = nil
+			}
+			assert.Equal(t, d.exp, cfg.Tasks)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/go-cliutil"
+)
+
+// LoadInherited loads configuration files in ancestor directories and merges them into cfg while `inherit` is true.
+// Settings of descendant configuration files take precedence.
+// Tasks of ancestor configuration files are run in the directory of the file.
+func (client *Client) LoadInherited(cfgFilePath, cfgFileName string, cfg *domain.Config) error {
+	p, err := filepath.Abs(cfgFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to get the absolute path of the configuration file %s: %w", cfgFilePath, err)
+	}
+	inherit := cfg.Inherit
+	for inherit {
+		dir := filepath.Dir(filepath.Dir(p))
+		if dir == filepath.Dir(p) {
+			return nil
+		}
+		parent, err := cliutil.FindFile(dir, existFile, fileNames(cfgFileName)...)
+		if err != nil {
+			// There is no configuration file in ancestor directories.
+			return nil //nolint:nilerr
+		}
+		base := &domain.Config{}
+		if err := client.Load(parent, base); err != nil {
+			return err
+		}
+		for i := range base.Tasks {
+			base.Tasks[i].Dir = filepath.Dir(parent)
+			// Merge ignores env_files of base, so they are set to tasks of base.
+			// Relative paths are relative to the task's directory.
+			base.Tasks[i].EnvFiles = append(slices.Clone(base.EnvFiles), base.Tasks[i].EnvFiles...)
+		}
+		Merge(cfg, base)
+		p = parent
+		inherit = base.Inherit
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+func TestClient_LoadInherited(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		files map[string]string
+		exp   map[string]string
+	}{
+		{
+			title: "inherit ancestors",
+			files: map[string]string{
+				".cmdx.yaml": `
+tasks:
+- name: lint
+  script: lint
+- name: root
+  script: root
+`,
+				"services/.cmdx.yaml": `
+inherit: true
+tasks:
+- name: lint
+  script: lint
+`,
+				"services/api/.cmdx.yaml": `
+inherit: true
+tasks:
+- name: build
+  script: build
+- name: lint
+  script: lint
+`,
+			},
+			exp: map[string]string{
+				"build": "",
+				"lint":  "",
+				"root":  ".",
+			},
+		},
+		{
+			title: "stop inheriting",
+			files: map[string]string{
+				".cmdx.yaml": `
+tasks:
+- name: root
+  script: root
+`,
+				"services/.cmdx.yaml": `
+tasks:
+- name: lint
+  script: lint
+`,
+				"services/api/.cmdx.yaml": `
+inherit: true
+tasks:
+- name: build
+  script: build
+`,
+			},
+			exp: map[string]string{
+				"build": "",
+				"lint":  "services",
+			},
+		},
+	}
+	client := New()
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			writeFiles(t, dir, d.files)
+			cfgFilePath := filepath.Join(dir, "services", "api", ".cmdx.yaml")
+			cfg := &domain.Config{}
+			require.NoError(t, client.Load(cfgFilePath, cfg))
+			require.NoError(t, client.LoadInherited(cfgFilePath, "", cfg))
+			dirs := make(map[string]string, len(cfg.Tasks))
+			for _, task := range cfg.Tasks {
+				if task.Dir == "" {
+					dirs[task.Name] = ""
+					continue
+				}
+				rel, err := filepath.Rel(dir, task.Dir)
+				require.NoError(t, err)
+				dirs[task.Name] = rel
+			}
+			assert.Equal(t, d.exp, dirs)
+		})
+	}
+}
+
+func TestClient_LoadInherited_envFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".cmdx.yaml": `
+env_files:
+- path: .env
+tasks:
+- name: root
+  env_files:
+  - path: .env.root
+  script: echo $FOO
+`,
+		"svc/.cmdx.yaml": `
+inherit: true
+tasks:
+- name: build
+  script: build
+`,
+	})
+	cfgFilePath := filepath.Join(dir, "svc", ".cmdx.yaml")
+	client := New()
+	cfg := &domain.Config{}
+	require.NoError(t, client.Load(cfgFilePath, cfg))
+	require.NoError(t, client.LoadInherited(cfgFilePath, "", cfg))
+	for _, task := range cfg.Tasks {
+		if task.Name == "root" {
+			assert.Equal(t, []domain.EnvFile{{Path: ".env"}, {Path: ".env.root"}}, task.EnvFiles)
+			return
+		}
+	}
+	t.Fatal("the task root isn't inherited")
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"go.yaml.in/yaml/v3"
+)
+
+// migration converts the configuration of a version into the next version.
+// It returns true if it rewrites nodes.
+type migration func(root *yaml.Node) (bool, error)
+
+// migrations[i] converts the configuration of the version i into the version i+1.
+// The configuration without `version` is the version 0, which has the same layout as the version 1.
+var migrations = []migration{ //nolint:gochecknoglobals
+	func(*yaml.Node) (bool, error) {
+		return false, nil
+	},
+}
+
+var versionValuePattern = regexp.MustCompile(`^["']?[0-9]+["']?`)
+
+// CheckVersion returns an error if the configuration version isn't supported.
+func CheckVersion(cfgFilePath string, version int) error {
+	if err := checkVersion(version); err != nil {
+		return fmt.Errorf("%w: %s", err, cfgFilePath)
+	}
+	return nil
+}
+
+func checkVersion(version int) error {
+	if version < 0 {
+		return errors.New("the configuration version must be greater than or equal to 0")
+	}
+	if version > domain.ConfigVersion {
+		return fmt.Errorf("the configuration version %d isn't supported by this cmdx. The latest supported version is %d. Please update cmdx", version, domain.ConfigVersion)
+	}
+	return nil
+}
+
+// Migrate converts the configuration file into the latest version.
+// If migrations only set the version, other lines are kept as they are.
+// Otherwise, comments are preserved, but the file may be reformatted.
+// Only YAML is supported.
+// It returns false if the configuration file is already the latest version.
+func (client *Client) Migrate(cfgFilePath string) (bool, error) {
+	if format(cfgFilePath) != formatYAML {
+		return false, fmt.Errorf("only YAML configuration files can be migrated: %s", cfgFilePath)
+	}
+	b, err := os.ReadFile(cfgFilePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read the configuration file %s: %w", cfgFilePath, err)
+	}
+	out, changed, err := migrate(b)
+	if err != nil {
+		return false, fmt.Errorf("failed to migrate the configuration file %s: %w", cfgFilePath, err)
+	}
+	if !changed {
+		return false, nil
+	}
+	if err := os.WriteFile(cfgFilePath, out, 0o644); err != nil { //nolint:gosec,mnd
+		return false, fmt.Errorf("failed to write the configuration file %s: %w", cfgFilePath, err)
+	}
+	return true, nil
+}
+
+func migrate(b []byte) ([]byte, bool, error) {
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(b, doc); err != nil {
+		return nil, false, fmt.Errorf("failed to parse the configuration file: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, false, errors.New("the configuration file must be a map")
+	}
+	root := doc.Content[0]
+	versionNode := mappingValue(root, "version")
+	version := 0
+	if versionNode != nil {
+		v, err := strconv.Atoi(versionNode.Value)
+		if err != nil {
+			return nil, false, fmt.Errorf("the configuration version must be an integer: %s", versionNode.Value)
+		}
+		version = v
+	}
+	if err := checkVersion(version); err != nil {
+		return nil, false, err
+	}
+	if version == domain.ConfigVersion {
+		return nil, false, nil
+	}
+	rewritten := false
+	for _, m := range migrations[version:] {
+		r, err := m(root)
+		if err != nil {
+			return nil, false, err
+		}
+		rewritten = rewritten || r
+	}
+	if !rewritten && root.Style&yaml.FlowStyle == 0 && len(root.Content) != 0 {
+		// Only the version is changed, so the file is edited as text to keep the format.
+		return setVersion(b, root, versionNode), true, nil
+	}
+	if versionNode == nil {
+		// add the version to the top of the configuration
+		// the comment at the top of the configuration is kept at the top
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+		if len(root.Content) != 0 {
+			key.HeadComment = root.Content[0].HeadComment
+			root.Content[0].HeadComment = ""
+		}
+		root.Content = append([]*yaml.Node{
+			key,
+			{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(domain.ConfigVersion)},
+		}, root.Content...)
+	} else {
+		versionNode.Value = strconv.Itoa(domain.ConfigVersion)
+	}
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2) //nolint:mnd
+	if err := enc.Encode(doc); err != nil {
+		return nil, false, fmt.Errorf("failed to encode the configuration: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, false, fmt.Errorf("failed to encode the configuration: %w", err)
+	}
+	return buf.Bytes(), true, nil
+}
+
+// setVersion sets the latest version to the configuration file without changing other lines.
+// If the version isn't set, the line "version: N" is inserted before the first key,
+// which is after the document marker "---" and comments at the top of the file.
+func setVersion(b []byte, root, versionNode *yaml.Node) []byte {
+	lines := strings.SplitAfter(string(b), "\n")
+	version := strconv.Itoa(domain.ConfigVersion)
+	if versionNode != nil {
+		i, col := versionNode.Line-1, versionNode.Column-1
+		lines[i] = lines[i][:col] + versionValuePattern.ReplaceAllString(lines[i][col:], version)
+		return []byte(strings.Join(lines, ""))
+	}
+	first := root.Content[0]
+	i := first.Line - 1
+	line := strings.Repeat(" ", first.Column-1) + "version: " + version + "\n"
+	lines = append(lines[:i], append([]string{line}, lines[i:]...)...)
+	return []byte(strings.Join(lines, ""))
+}
+
+// mappingValue returns the value of the key in the mapping node.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
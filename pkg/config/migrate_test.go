@@ -0,0 +1,118 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_migrate(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title   string
+		src     string
+		exp     string
+		changed bool
+		expErr  string
+	}{
+		{
+			title: "add the version",
+			src: `# the configuration of cmdx
+tasks:
+    # hello task
+    - name: hello
+      script: echo hello # comment
+`,
+			exp: `# the configuration of cmdx
+version: 1
+tasks:
+    # hello task
+    - name: hello
+      script: echo hello # comment
+`,
+			changed: true,
+		},
+		{
+			title: "other lines are kept byte for byte",
+			src: `---
+# yaml-language-server: $schema=https://example.com/cmdx.json
+
+# the configuration of cmdx
+tasks:
+- name: hello   # comment
+  script: "echo hello"
+
+- name: bye
+  script: |
+      echo bye
+`,
+			exp: `---
+# yaml-language-server: $schema=https://example.com/cmdx.json
+
+# the configuration of cmdx
+version: 1
+tasks:
+- name: hello   # comment
+  script: "echo hello"
+
+- name: bye
+  script: |
+      echo bye
+`,
+			changed: true,
+		},
+		{
+			title: "update the version 0",
+			src: `tasks: []
+version: "0" # the old version
+`,
+			exp: `tasks: []
+version: 1 # the old version
+`,
+			changed: true,
+		},
+		{
+			title: "latest version",
+			src: `version: 1
+tasks: []
+`,
+		},
+		{
+			title: "future version",
+			src: `version: 100
+tasks: []
+`,
+			expErr: "the configuration version 100 isn't supported by this cmdx. The latest supported version is 1. Please update cmdx",
+		},
+		{
+			title: "invalid version",
+			src: `version: foo
+tasks: []
+`,
+			expErr: "the configuration version must be an integer: foo",
+		},
+		{
+			title: "negative version",
+			src: `version: -1
+tasks: []
+`,
+			expErr: "the configuration version must be greater than or equal to 0",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			out, changed, err := migrate([]byte(d.src))
+			if d.expErr != "" {
+				require.EqualError(t, err, d.expErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, d.changed, changed)
+			if changed {
+				assert.Equal(t, d.exp, string(out))
+			}
+		})
+	}
+}
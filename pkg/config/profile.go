@@ -0,0 +1,57 @@
+package config
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+// ApplyProfile overrides the configuration with the profile.
+// environment, input_envs, and timeout of the profile override the configuration level settings,
+// so the task level settings still take precedence over them.
+// flags of the profile override default values of flags of all tasks.
+func ApplyProfile(cfg *domain.Config, name string) error {
+	if name == "" {
+		return nil
+	}
+	profile, ok := cfg.Profiles[name]
+	if !ok {
+		names := slices.Sorted(maps.Keys(cfg.Profiles))
+		return errors.New("the profile isn't found: " + name + ". Available profiles: " + strings.Join(names, ", "))
+	}
+	if len(profile.Environment) != 0 {
+		if cfg.Environment == nil {
+			cfg.Environment = make(map[string]string, len(profile.Environment))
+		}
+		maps.Copy(cfg.Environment, profile.Environment)
+	}
+	if len(profile.InputEnvs) != 0 {
+		cfg.InputEnvs = profile.InputEnvs
+	}
+	if profile.Timeout.Duration != 0 {
+		cfg.Timeout.Duration = profile.Timeout.Duration
+	}
+	if profile.Timeout.KillAfter != 0 {
+		cfg.Timeout.KillAfter = profile.Timeout.KillAfter
+	}
+	applyProfileFlags(cfg.Tasks, profile.Flags)
+	return nil
+}
+
+func applyProfileFlags(tasks []domain.Task, flags map[string]string) {
+	if len(flags) == 0 {
+		return
+	}
+	for i := range tasks {
+		task := &tasks[i]
+		for j, flag := range task.Flags {
+			if v, ok := flags[flag.Name]; ok {
+				task.Flags[j].Default = v
+			}
+		}
+		applyProfileFlags(task.Tasks, flags)
+	}
+}
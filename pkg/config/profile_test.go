@@ -0,0 +1,81 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+func TestApplyProfile(t *testing.T) {
+	t.Parallel()
+	newConfig := func() *domain.Config {
+		return &domain.Config{
+			Environment: map[string]string{"STAGE": "dev", "APP": "api"},
+			Timeout:     domain.Timeout{Duration: 60, KillAfter: 10},
+			Tasks: []domain.Task{
+				{
+					Name:  "deploy",
+					Flags: []domain.Flag{{Name: "region", Default: "us-east-1"}, {Name: "force", Type: "bool"}},
+					Tasks: []domain.Task{
+						{
+							Name:  "db",
+							Flags: []domain.Flag{{Name: "region"}},
+						},
+					},
+				},
+			},
+			Profiles: map[string]domain.Profile{
+				"prod": {
+					Environment: map[string]string{"STAGE": "prod"},
+					Flags:       map[string]string{"region": "ap-northeast-1"},
+					InputEnvs:   []string{"PROD_{{.name}}"},
+					Timeout:     domain.Timeout{Duration: 600},
+				},
+			},
+		}
+	}
+	data := []struct {
+		title   string
+		profile string
+		exp     *domain.Config
+		isErr   bool
+	}{
+		{
+			title: "no profile",
+			exp:   newConfig(),
+		},
+		{
+			title:   "override the configuration",
+			profile: "prod",
+			exp: func() *domain.Config {
+				cfg := newConfig()
+				cfg.Environment = map[string]string{"STAGE": "prod", "APP": "api"}
+				cfg.InputEnvs = []string{"PROD_{{.name}}"}
+				cfg.Timeout = domain.Timeout{Duration: 600, KillAfter: 10}
+				cfg.Tasks[0].Flags[0].Default = "ap-northeast-1"
+				cfg.Tasks[0].Tasks[0].Flags[0].Default = "ap-northeast-1"
+				return cfg
+			}(),
+		},
+		{
+			title:   "unknown profile",
+			profile: "staging",
+			isErr:   true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			cfg := newConfig()
+			err := ApplyProfile(cfg, d.profile)
+			if d.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, cfg)
+		})
+	}
+}
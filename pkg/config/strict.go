@@ -0,0 +1,88 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"go.yaml.in/yaml/v3"
+)
+
+var unknownFieldPattern = regexp.MustCompile(`^line (\d+): field (\S+) not found in type \S+$`)
+
+// checkUnknownKeys returns an error if the configuration has unknown keys.
+// Each unknown key is reported with the position "file:line:column".
+// If the position is unknown, only the file path is reported.
+func checkUnknownKeys(cfgFilePath string, doc *document) error {
+	dec := yaml.NewDecoder(bytes.NewReader(doc.src))
+	dec.KnownFields(true)
+	if err := dec.Decode(&domain.Config{}); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return fmt.Errorf("%s: %w", cfgFilePath, err)
+		}
+		// Line numbers of errors refer to doc.src, which may be converted from the original file.
+		// Unknown keys are looked up in doc.src and the keys at the same path in doc.root are reported.
+		src := &yaml.Node{}
+		if err := yaml.Unmarshal(doc.src, src); err != nil {
+			return fmt.Errorf("%s: %w", cfgFilePath, err)
+		}
+		errs := make([]error, len(typeErr.Errors))
+		for i, msg := range typeErr.Errors {
+			errs[i] = unknownKeyError(cfgFilePath, src, doc.root, msg)
+		}
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+func unknownKeyError(cfgFilePath string, src, root *yaml.Node, msg string) error {
+	m := unknownFieldPattern.FindStringSubmatch(msg)
+	if m == nil {
+		return fmt.Errorf("%s: %s", cfgFilePath, msg)
+	}
+	line, err := strconv.Atoi(m[1])
+	if err != nil {
+		return fmt.Errorf("%s: %s", cfgFilePath, msg)
+	}
+	key := nodeAt(root, findKey(src, line, m[2]))
+	if key == nil || key.Line == 0 {
+		return fmt.Errorf("%s: unknown key %q", cfgFilePath, m[2])
+	}
+	return fmt.Errorf("%s: unknown key %q", domain.Position(cfgFilePath, key.Line, key.Column), m[2])
+}
+
+// findKey returns the path of the mapping key node at the line.
+// The path is a list of indexes of Content.
+func findKey(node *yaml.Node, line int, key string) []int {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if k := node.Content[i]; k.Line == line && k.Value == key {
+				return []int{i}
+			}
+		}
+	}
+	for i, c := range node.Content {
+		if p := findKey(c, line, key); p != nil {
+			return append([]int{i}, p...)
+		}
+	}
+	return nil
+}
+
+// nodeAt returns the node at the path which is returned by findKey.
+func nodeAt(node *yaml.Node, path []int) *yaml.Node {
+	if path == nil {
+		return nil
+	}
+	for _, i := range path {
+		if i >= len(node.Content) {
+			return nil
+		}
+		node = node.Content[i]
+	}
+	return node
+}
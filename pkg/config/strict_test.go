@@ -0,0 +1,58 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkUnknownKeys(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		src   string
+		exp   string
+	}{
+		{
+			title: "no unknown key",
+			src: `tasks:
+- name: foo
+  environment:
+    FOO: foo
+  script: echo foo
+`,
+		},
+		{
+			title: "unknown keys",
+			src: `tasks:
+- name: foo
+  enviroment:
+    FOO: foo
+  flags:
+  - name: bar
+    typ: bool
+  env_files:
+  - path: .env
+    optionl: true
+`,
+			exp: `.cmdx.yaml:3:3: unknown key "enviroment"
+.cmdx.yaml:7:5: unknown key "typ"
+.cmdx.yaml:10:5: unknown key "optionl"`,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			doc, err := parseYAML(".cmdx.yaml", []byte(d.src))
+			require.NoError(t, err)
+			err = checkUnknownKeys(".cmdx.yaml", doc)
+			if d.exp == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, d.exp, err.Error())
+		})
+	}
+}
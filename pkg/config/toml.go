@@ -0,0 +1,249 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2/unstable"
+	"go.yaml.in/yaml/v3"
+)
+
+// tomlConverter converts TOML into the YAML node.
+// Each node has the position in the TOML file so that errors can be outputted with positions.
+type tomlConverter struct {
+	path   string
+	parser *unstable.Parser
+}
+
+// parseTOML converts TOML into the YAML document node.
+func parseTOML(cfgFilePath string, b []byte) (*yaml.Node, error) {
+	c := &tomlConverter{
+		path:   cfgFilePath,
+		parser: &unstable.Parser{},
+	}
+	c.parser.Reset(b)
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: 1, Column: 1}
+	current := root
+	for c.parser.NextExpression() {
+		expr := c.parser.Expression()
+		var err error
+		switch expr.Kind { //nolint:exhaustive
+		case unstable.KeyValue:
+			err = c.keyValue(current, expr)
+		case unstable.Table:
+			current, err = c.table(root, expr, false)
+		case unstable.ArrayTable:
+			current, err = c.table(root, expr, true)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := c.parser.Error(); err != nil {
+		return nil, c.error(err)
+	}
+	return &yaml.Node{
+		Kind:    yaml.DocumentNode,
+		Line:    1,
+		Column:  1,
+		Content: []*yaml.Node{root},
+	}, nil
+}
+
+// error adds the position to the error of the TOML parser.
+func (c *tomlConverter) error(err error) error {
+	var parserErr *unstable.ParserError
+	if !errors.As(err, &parserErr) || parserErr.Highlight == nil {
+		return fmt.Errorf("%s: %w", c.path, err)
+	}
+	shape := c.parser.Shape(c.parser.Range(parserErr.Highlight))
+	return c.errorf(shape.Start.Line, shape.Start.Column, "%w", err)
+}
+
+func (c *tomlConverter) errorf(line, column int, format string, a ...any) error {
+	return fmt.Errorf("%s:%d:%d: %w", c.path, line, column, fmt.Errorf(format, a...))
+}
+
+// setPosition sets the position of the TOML node to the YAML node.
+// Nodes such as arrays don't have positions, so the position of the key is used.
+func (c *tomlConverter) setPosition(node *yaml.Node, n *unstable.Node, key *yaml.Node) {
+	if n.Raw.Length == 0 {
+		node.Line = key.Line
+		node.Column = key.Column
+		return
+	}
+	shape := c.parser.Shape(n.Raw)
+	node.Line = shape.Start.Line
+	node.Column = shape.Start.Column
+}
+
+func (c *tomlConverter) keyNode(n *unstable.Node) *yaml.Node {
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(n.Data)}
+	c.setPosition(key, n, key)
+	return key
+}
+
+// keys returns the dotted key of the node.
+func (c *tomlConverter) keys(n *unstable.Node) []*yaml.Node {
+	keys := []*yaml.Node{}
+	it := n.Key()
+	for it.Next() {
+		keys = append(keys, c.keyNode(it.Node()))
+	}
+	return keys
+}
+
+// child returns the mapping node of the key.
+// If the key doesn't exist, the mapping node is created.
+// If the value of the key is an array of tables, the last table is returned.
+func (c *tomlConverter) child(node, key *yaml.Node) (*yaml.Node, error) {
+	if v := mappingValue(node, key.Value); v != nil {
+		if v.Kind == yaml.SequenceNode && len(v.Content) != 0 {
+			v = v.Content[len(v.Content)-1]
+		}
+		if v.Kind != yaml.MappingNode {
+			return nil, c.errorf(key.Line, key.Column, "the key %q is already defined as a value", key.Value)
+		}
+		return v, nil
+	}
+	v := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: key.Line, Column: key.Column}
+	node.Content = append(node.Content, key, v)
+	return v, nil
+}
+
+func (c *tomlConverter) table(root *yaml.Node, expr *unstable.Node, array bool) (*yaml.Node, error) {
+	keys := c.keys(expr)
+	node := root
+	for _, key := range keys[:len(keys)-1] {
+		child, err := c.child(node, key)
+		if err != nil {
+			return nil, err
+		}
+		node = child
+	}
+	key := keys[len(keys)-1]
+	if !array {
+		return c.child(node, key)
+	}
+	tbl := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: key.Line, Column: key.Column}
+	if seq := mappingValue(node, key.Value); seq != nil {
+		if seq.Kind != yaml.SequenceNode {
+			return nil, c.errorf(key.Line, key.Column, "the key %q is already defined as a value", key.Value)
+		}
+		seq.Content = append(seq.Content, tbl)
+		return tbl, nil
+	}
+	node.Content = append(node.Content, key, &yaml.Node{
+		Kind:    yaml.SequenceNode,
+		Tag:     "!!seq",
+		Line:    key.Line,
+		Column:  key.Column,
+		Content: []*yaml.Node{tbl},
+	})
+	return tbl, nil
+}
+
+func (c *tomlConverter) keyValue(node *yaml.Node, expr *unstable.Node) error {
+	keys := c.keys(expr)
+	for _, key := range keys[:len(keys)-1] {
+		child, err := c.child(node, key)
+		if err != nil {
+			return err
+		}
+		node = child
+	}
+	key := keys[len(keys)-1]
+	if mappingValue(node, key.Value) != nil {
+		return c.errorf(key.Line, key.Column, "the key %q is defined twice", key.Value)
+	}
+	value, err := c.value(expr.Value(), key)
+	if err != nil {
+		return err
+	}
+	node.Content = append(node.Content, key, value)
+	return nil
+}
+
+func (c *tomlConverter) value(n *unstable.Node, key *yaml.Node) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.ScalarNode}
+	c.setPosition(node, n, key)
+	switch n.Kind { //nolint:exhaustive
+	case unstable.String:
+		node.Tag = "!!str"
+		node.Value = string(n.Data)
+	case unstable.Bool:
+		node.Tag = "!!bool"
+		node.Value = string(n.Data)
+	case unstable.Integer:
+		i, err := strconv.ParseInt(string(n.Data), 0, 64)
+		if err != nil {
+			return nil, c.errorf(node.Line, node.Column, "the integer is invalid: %w", err)
+		}
+		node.Tag = "!!int"
+		node.Value = strconv.FormatInt(i, 10)
+	case unstable.Float:
+		f, err := parseTOMLFloat(string(n.Data))
+		if err != nil {
+			return nil, c.errorf(node.Line, node.Column, "the float is invalid: %w", err)
+		}
+		node.Tag = "!!float"
+		node.Value = f
+	case unstable.LocalDate, unstable.LocalTime, unstable.LocalDateTime, unstable.DateTime:
+		node.Tag = "!!str"
+		node.Value = string(n.Data)
+	case unstable.Array:
+		node.Kind = yaml.SequenceNode
+		node.Tag = "!!seq"
+		it := n.Children()
+		for it.Next() {
+			child := it.Node()
+			if child.Kind == unstable.Comment {
+				continue
+			}
+			v, err := c.value(child, node)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, v)
+		}
+	case unstable.InlineTable:
+		node.Kind = yaml.MappingNode
+		node.Tag = "!!map"
+		it := n.Children()
+		for it.Next() {
+			child := it.Node()
+			if child.Kind != unstable.KeyValue {
+				continue
+			}
+			if err := c.keyValue(node, child); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, c.errorf(node.Line, node.Column, "the value isn't supported: %s", n.Kind)
+	}
+	return node, nil
+}
+
+func parseTOMLFloat(s string) (string, error) {
+	switch strings.TrimLeft(s, "+-") {
+	case "inf":
+		if strings.HasPrefix(s, "-") {
+			return "-.inf", nil
+		}
+		return ".inf", nil
+	case "nan":
+		return ".nan", nil
+	}
+	f, err := strconv.ParseFloat(strings.ReplaceAll(s, "_", ""), 64)
+	if err != nil {
+		return "", err
+	}
+	if math.IsInf(f, 0) {
+		return "", errors.New("the float is out of range")
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64), nil
+}
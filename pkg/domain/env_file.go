@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"github.com/invopop/jsonschema"
+)
+
+// EnvFile is a dotenv file.
+// If Optional is true, the file is skipped if it doesn't exist.
+type EnvFile struct { //nolint:recvcheck
+	Path     string `json:"path"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+func (EnvFile) JSONSchema() *jsonschema.Schema {
+	props := jsonschema.NewProperties()
+	props.Set("path", &jsonschema.Schema{
+		Type:        jsonSchemaTypeString,
+		Description: "the file path. The relative path is relative to the configuration file",
+	})
+	props.Set("optional", &jsonschema.Schema{
+		Type:        "boolean",
+		Description: "if true, the file is skipped if it doesn't exist",
+	})
+	return &jsonschema.Schema{
+		OneOf: []*jsonschema.Schema{
+			{
+				Type: jsonSchemaTypeString,
+			},
+			{
+				Type:                 "object",
+				Properties:           props,
+				Required:             []string{"path"},
+				AdditionalProperties: jsonschema.FalseSchema,
+			},
+		},
+	}
+}
+
+func (f *EnvFile) UnmarshalYAML(unmarshal func(any) error) error {
+	var p string
+	if err := unmarshal(&p); err == nil {
+		f.Path = p
+		return nil
+	}
+	type alias EnvFile
+	a := alias{}
+	if err := unmarshal(&a); err != nil {
+		return err
+	}
+	*f = EnvFile(a)
+	return nil
+}
@@ -2,12 +2,20 @@ package domain
 
 import (
 	"github.com/suzuki-shunsuke/cmdx/pkg/prompt"
+	"go.yaml.in/yaml/v3"
 )
 
 type GlobalFlags struct {
 	DryRun     bool
 	Quiet      *bool
 	WorkingDir string
+	Force      bool
+	// Only is filters of matrix combinations such as "goos=linux".
+	Only []string
+	// ConfigDir is the directory where the configuration file exists.
+	ConfigDir string
+	// Profile is the name of the active profile.
+	Profile string
 }
 
 type Flag struct {
@@ -24,21 +32,72 @@ type Flag struct {
 }
 
 type Task struct {
-	Name        string            `json:"name"`
-	Short       string            `json:"short,omitempty"`
-	Description string            `json:"description,omitempty"`
-	Usage       string            `json:"usage,omitempty"`
-	Flags       []Flag            `json:"flags,omitempty"`
-	Args        []Arg             `json:"args,omitempty"`
-	InputEnvs   []string          `json:"input_envs,omitempty" yaml:"input_envs"`
-	ScriptEnvs  []string          `json:"script_envs,omitempty" yaml:"script_envs"`
-	Environment map[string]string `json:"environment,omitempty"`
+	Name        string              `json:"name"`
+	Short       string              `json:"short,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Usage       string              `json:"usage,omitempty"`
+	Flags       []Flag              `json:"flags,omitempty"`
+	Args        []Arg               `json:"args,omitempty"`
+	InputEnvs   []string            `json:"input_envs,omitempty" yaml:"input_envs"`
+	ScriptEnvs  []string            `json:"script_envs,omitempty" yaml:"script_envs"`
+	Environment map[string]string   `json:"environment,omitempty"`
+	Script      string              `json:"script,omitempty"`
+	Timeout     Timeout             `json:"timeout,omitzero"`
+	Require     Require             `json:"require,omitzero"`
+	Quiet       *bool               `json:"quiet,omitempty"`
+	Shell       []string            `json:"shell,omitempty"`
+	Tasks       []Task              `json:"tasks,omitempty"`
+	DependsOn   []string            `json:"depends_on,omitempty" yaml:"depends_on"`
+	Steps       []Step              `json:"steps,omitempty"`
+	Sources     []string            `json:"sources,omitempty"`
+	Generates   []string            `json:"generates,omitempty"`
+	Status      []string            `json:"status,omitempty"`
+	Matrix      map[string][]string `json:"matrix,omitempty"`
+	Retry       Retry               `json:"retry,omitzero"`
+	If          string              `json:"if,omitempty"`
+	ForEach     string              `json:"for_each,omitempty" yaml:"for_each"`
+	Vars        map[string]Var      `json:"vars,omitempty"`
+	EnvFiles    []EnvFile           `json:"env_files,omitempty" yaml:"env_files"`
+	Extends     string              `json:"extends,omitempty"`
+	// Source is the path of the configuration file where the task is defined.
+	Source string `json:"-" yaml:"-"`
+	// Dir is the working directory of the task.
+	// This is set to tasks of configuration files in ancestor directories.
+	Dir string `json:"-" yaml:"-"`
+	// Node is the YAML node of the task, which is used to output the position of invalid settings.
+	Node  *yaml.Node `json:"-" yaml:"-"`
+	Hooks `yaml:",inline"`
+}
+
+// Retry is the retry policy of the task.
+type Retry struct {
+	Attempts    int    `json:"attempts,omitempty"`
+	Delay       int    `json:"delay,omitempty"`
+	Backoff     string `json:"backoff,omitempty" jsonschema:"enum=constant,enum=exponential"`
+	OnExitCodes []int  `json:"on_exit_codes,omitempty" yaml:"on_exit_codes"`
+}
+
+// Hooks are scripts run around the task.
+// finally is run even if the task fails.
+type Hooks struct {
+	Before  string `json:"before,omitempty"`
+	After   string `json:"after,omitempty"`
+	Finally string `json:"finally,omitempty"`
+}
+
+type Step struct {
+	Name        string            `json:"name,omitempty"`
 	Script      string            `json:"script,omitempty"`
-	Timeout     Timeout           `json:"timeout,omitzero"`
-	Require     Require           `json:"require,omitzero"`
-	Quiet       *bool             `json:"quiet,omitempty"`
+	Task        string            `json:"task,omitempty"`
+	Flags       map[string]string `json:"flags,omitempty"`
+	Args        []string          `json:"args,omitempty"`
 	Shell       []string          `json:"shell,omitempty"`
-	Tasks       []Task            `json:"tasks,omitempty"`
+	Environment map[string]string `json:"environment,omitempty"`
+	Dir         string            `json:"dir,omitempty"`
+	Timeout     Timeout           `json:"timeout,omitzero"`
+	IgnoreError bool              `json:"ignore_error,omitempty" yaml:"ignore_error"`
+	If          string            `json:"if,omitempty"`
+	ForEach     string            `json:"for_each,omitempty" yaml:"for_each"`
 }
 
 type Arg struct {
@@ -62,13 +121,42 @@ type Timeout struct {
 	KillAfter int `json:"kill_after,omitempty" yaml:"kill_after"`
 }
 
+// ConfigVersion is the latest version of the configuration.
+const ConfigVersion = 1
+
 type Config struct {
-	Tasks       []Task            `json:"tasks"`
-	InputEnvs   []string          `json:"input_envs,omitempty" yaml:"input_envs"`
-	ScriptEnvs  []string          `json:"script_envs,omitempty" yaml:"script_envs"`
+	Version     int                `json:"version,omitempty" jsonschema:"enum=1"`
+	Strict      *bool              `json:"strict,omitempty"`
+	Tasks       []Task             `json:"tasks"`
+	InputEnvs   []string           `json:"input_envs,omitempty" yaml:"input_envs"`
+	ScriptEnvs  []string           `json:"script_envs,omitempty" yaml:"script_envs"`
+	Environment map[string]string  `json:"environment,omitempty"`
+	Timeout     Timeout            `json:"timeout,omitzero"`
+	Quiet       *bool              `json:"quiet,omitempty"`
+	Concurrency int                `json:"concurrency,omitempty"`
+	Includes    []Include          `json:"includes,omitempty"`
+	Inherit     bool               `json:"inherit,omitempty"`
+	Vars        map[string]Var     `json:"vars,omitempty"`
+	EnvFiles    []EnvFile          `json:"env_files,omitempty" yaml:"env_files"`
+	Profiles    map[string]Profile `json:"profiles,omitempty"`
+	Hooks       `yaml:",inline"`
+}
+
+// Profile overrides the configuration when the profile is selected by --profile.
+// Flags are default values of flags, whose keys are flag names.
+type Profile struct {
 	Environment map[string]string `json:"environment,omitempty"`
+	Flags       map[string]string `json:"flags,omitempty"`
+	InputEnvs   []string          `json:"input_envs,omitempty" yaml:"input_envs"`
 	Timeout     Timeout           `json:"timeout,omitzero"`
-	Quiet       *bool             `json:"quiet,omitempty"`
+}
+
+// Include is a configuration file which is included.
+// Path is a file path or a glob pattern, which is relative to the including file.
+// If Namespace is set, task names are prefixed with "<namespace>:".
+type Include struct {
+	Path      string `json:"path"`
+	Namespace string `json:"namespace,omitempty"`
 }
 
 type Validate struct {
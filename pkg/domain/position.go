@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"path/filepath"
+	"strconv"
+)
+
+// Position returns the position of the setting in the configuration file such as "cmdx.yaml:3:5".
+// Jsonnet configuration files are evaluated into JSON and the positions refer to the evaluated JSON,
+// which can be outputted by `jsonnet <file>`, because they don't correspond with the Jsonnet file.
+func Position(file string, line, column int) string {
+	if filepath.Ext(file) == ".jsonnet" {
+		file += " (evaluated JSON)"
+	}
+	return file + ":" + strconv.Itoa(line) + ":" + strconv.Itoa(column)
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/invopop/jsonschema"
+)
+
+// Var is a variable, which is either a literal or template Value or a command Sh.
+// The standard output of Sh is the value of the variable.
+type Var struct { //nolint:recvcheck
+	Value string
+	Sh    string
+}
+
+func (Var) JSONSchema() *jsonschema.Schema {
+	props := jsonschema.NewProperties()
+	props.Set("sh", &jsonschema.Schema{
+		Type:        jsonSchemaTypeString,
+		Description: "the command whose standard output is the value of the variable",
+	})
+	return &jsonschema.Schema{
+		OneOf: []*jsonschema.Schema{
+			{
+				Type: jsonSchemaTypeString,
+			},
+			{
+				Type:                 "object",
+				Properties:           props,
+				Required:             []string{"sh"},
+				AdditionalProperties: jsonschema.FalseSchema,
+			},
+		},
+	}
+}
+
+func (v *Var) UnmarshalYAML(unmarshal func(any) error) error {
+	var val any
+	if err := unmarshal(&val); err != nil {
+		return err
+	}
+	switch a := val.(type) {
+	case string:
+		v.Value = a
+		return nil
+	case map[string]any:
+		if len(a) != 1 {
+			return errors.New("the variable must have only the key 'sh'")
+		}
+		sh, ok := a["sh"].(string)
+		if !ok {
+			return errors.New("the variable must have the string 'sh'")
+		}
+		v.Sh = sh
+		return nil
+	case nil:
+		return nil
+	case []any:
+		return errors.New("the variable must be either a string or a map")
+	default:
+		v.Value = fmt.Sprint(a)
+		return nil
+	}
+}
+
+func (v Var) MarshalJSON() ([]byte, error) {
+	if v.Sh == "" {
+		return json.Marshal(v.Value)
+	}
+	return json.Marshal(map[string]string{"sh": v.Sh})
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+)
+
+func TestVar_UnmarshalYAML(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		isErr bool
+		src   string
+		exp   Var
+	}{
+		{
+			title: "string",
+			src:   `"v{{.major}}"`,
+			exp:   Var{Value: "v{{.major}}"},
+		},
+		{
+			title: "number",
+			src:   `1`,
+			exp:   Var{Value: "1"},
+		},
+		{
+			title: "sh",
+			src:   `{sh: git rev-parse --short HEAD}`,
+			exp:   Var{Sh: "git rev-parse --short HEAD"},
+		},
+		{
+			title: "unknown key",
+			src:   `{cmd: git rev-parse --short HEAD}`,
+			isErr: true,
+		},
+		{
+			title: "list",
+			src:   `[foo]`,
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			v := Var{}
+			err := yaml.Unmarshal([]byte(d.src), &v)
+			if d.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, v)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package dotenv
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Parse parses the content of the dotenv file and sets variables to env.
+// Lines are "KEY=VALUE" and can have the prefix "export ".
+// Comments start with "#".
+// Values can be quoted with single or double quotes, and double quoted values can span multiple lines.
+// "${KEY}" and "$KEY" in unquoted and double quoted values are expanded by env and getenv in this order.
+func Parse(content string, env map[string]string, getenv func(string) (string, bool)) error {
+	lookup := func(key string) string {
+		if v, ok := env[key]; ok {
+			return v
+		}
+		v, _ := getenv(key)
+		return v
+	}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("line %d: the line must be KEY=VALUE", lineNum)
+		}
+		key = strings.TrimSpace(key)
+		if key == "" || strings.ContainsAny(key, " \t") {
+			return fmt.Errorf("line %d: the key is invalid: %s", lineNum, key)
+		}
+		val = strings.TrimSpace(val)
+		switch {
+		case strings.HasPrefix(val, "'"):
+			i := strings.IndexByte(val[1:], '\'')
+			if i == -1 {
+				return fmt.Errorf("line %d: the single quote isn't closed", lineNum)
+			}
+			if rest := strings.TrimSpace(val[i+2:]); rest != "" && !strings.HasPrefix(rest, "#") {
+				return fmt.Errorf("line %d: unexpected characters after the single quote: %s", lineNum, rest)
+			}
+			env[key] = val[1 : i+1]
+		case strings.HasPrefix(val, `"`):
+			s := val[1:]
+			for !isClosed(s) {
+				if !scanner.Scan() {
+					return fmt.Errorf("line %d: the double quote isn't closed", lineNum)
+				}
+				lineNum++
+				s += "\n" + scanner.Text()
+			}
+			s, err := unquote(s)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			env[key] = expand(s, lookup, true)
+		default:
+			if i := strings.Index(val, " #"); i != -1 {
+				val = strings.TrimSpace(val[:i])
+			}
+			env[key] = expand(val, lookup, false)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read the dotenv file: %w", err)
+	}
+	return nil
+}
+
+// isClosed returns true if s has the unescaped double quote.
+func isClosed(s string) bool {
+	_, ok := closingQuote(s)
+	return ok
+}
+
+// closingQuote returns the index of the unescaped double quote.
+func closingQuote(s string) (int, bool) {
+	escaped := false
+	for i, c := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// unquote returns the content of the double quoted value whose leading quote is removed.
+// The characters after the closing quote must be a comment.
+func unquote(s string) (string, error) {
+	i, ok := closingQuote(s)
+	if !ok {
+		return "", errors.New("the double quote isn't closed")
+	}
+	if rest := strings.TrimSpace(s[i+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+		return "", fmt.Errorf("unexpected characters after the double quote: %s", rest)
+	}
+	return s[:i], nil
+}
+
+// expand expands "${KEY}" and "$KEY" by lookup.
+// If escape is true, escape sequences such as "\\n" and "\\$" are interpreted.
+func expand(s string, lookup func(string) string, escape bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escape && c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			default:
+				b.WriteByte(s[i])
+			}
+		case c == '$' && i+1 < len(s) && s[i+1] == '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end == -1 {
+				b.WriteString(s[i:])
+				return b.String()
+			}
+			b.WriteString(lookup(s[i+2 : i+end]))
+			i += end
+		case c == '$' && i+1 < len(s) && isNameChar(s[i+1]):
+			j := i + 1
+			for j < len(s) && isNameChar(s[j]) {
+				j++
+			}
+			b.WriteString(lookup(s[i+1 : j]))
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// ReadFile reads the dotenv file and sets variables to env.
+func ReadFile(p string, env map[string]string, getenv func(string) (string, bool)) error {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return fmt.Errorf("failed to read the dotenv file %s: %w", p, err)
+	}
+	if err := Parse(string(b), env, getenv); err != nil {
+		return fmt.Errorf("failed to parse the dotenv file %s: %w", p, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package dotenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title   string
+		content string
+		env     map[string]string
+		exp     map[string]string
+		isErr   bool
+	}{
+		{
+			title: "normal",
+			content: `# comment
+export FOO=foo
+BAR = bar baz # comment
+
+SINGLE='${FOO} # not comment' # comment
+DOUBLE="${FOO}\n\$FOO \"quoted\""
+EXPAND=$FOO-${BAR}-${HOME}-${UNKNOWN}
+MULTI="line1
+line2"
+`,
+			exp: map[string]string{
+				"FOO":    "foo",
+				"BAR":    "bar baz",
+				"SINGLE": "${FOO} # not comment",
+				"DOUBLE": "foo\n$FOO \"quoted\"",
+				"EXPAND": "foo-bar baz-/home/foo-",
+				"MULTI":  "line1\nline2",
+			},
+		},
+		{
+			title:   "refer the previous file",
+			content: `BAR=${FOO}bar`,
+			env:     map[string]string{"FOO": "foo"},
+			exp:     map[string]string{"FOO": "foo", "BAR": "foobar"},
+		},
+		{
+			title:   "no equal",
+			content: `FOO`,
+			isErr:   true,
+		},
+		{
+			title:   "the double quote isn't closed",
+			content: `FOO="foo`,
+			isErr:   true,
+		},
+		{
+			title:   "the single quote isn't closed",
+			content: `FOO='foo`,
+			isErr:   true,
+		},
+	}
+	getenv := func(k string) (string, bool) {
+		if k == "HOME" {
+			return "/home/foo", true
+		}
+		return "", false
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			env := d.env
+			if env == nil {
+				env = map[string]string{}
+			}
+			err := Parse(d.content, env, getenv)
+			if d.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, env)
+		})
+	}
+}
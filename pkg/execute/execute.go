@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"time"
@@ -25,6 +26,11 @@ type Params struct {
 	Quiet      bool
 	DryRun     bool
 	Timeout    *Timeout
+	// OutputPrefix is the prefix of each line of the output.
+	// If OutputPrefix is empty, the output isn't changed.
+	OutputPrefix string
+	// Silent discards the output of the command.
+	Silent bool
 }
 
 type Timeout struct {
@@ -54,15 +60,31 @@ func (exc *Executor) Run(ctx context.Context, params *Params) error {
 			shell = []string{"bash", "-euo", "pipefail", "-c"}
 		}
 	}
+	if params.Timeout.Duration > 0 {
+		// The timeout must be set before the command is created so that the command is terminated by the timeout.
+		c, cancel := context.WithTimeout(ctx, params.Timeout.Duration)
+		defer cancel()
+		ctx = c
+	}
 	cmd := exec.CommandContext(ctx, shell[0], append(shell[1:], params.Script)...) //nolint:gosec
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	var stdout, stderr io.Writer = os.Stdout, os.Stderr
+	if params.Silent {
+		stdout, stderr = io.Discard, io.Discard
+	} else if params.OutputPrefix != "" {
+		pStdout := newPrefixWriter(os.Stdout, params.OutputPrefix)
+		pStderr := newPrefixWriter(os.Stderr, params.OutputPrefix)
+		defer pStdout.Flush() //nolint:errcheck
+		defer pStderr.Flush() //nolint:errcheck
+		stdout, stderr = pStdout, pStderr
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
 	cmd.Stdin = os.Stdin
 	cmd.Dir = params.WorkingDir
 
 	cmd.Env = append(os.Environ(), params.Envs...)
 	if !params.Quiet {
-		fmt.Fprintln(os.Stderr, "+ "+params.Script)
+		fmt.Fprintln(stderr, "+ "+params.Script)
 	}
 	if params.DryRun {
 		return nil
@@ -70,16 +92,11 @@ func (exc *Executor) Run(ctx context.Context, params *Params) error {
 
 	setCancel(cmd, params.Timeout.KillAfter)
 
-	if params.Timeout.Duration > 0 {
-		c, cancel := context.WithTimeout(ctx, params.Timeout.Duration)
-		defer cancel()
-		ctx = c
-	}
 	go func() {
 		<-ctx.Done()
 		err := ctx.Err()
 		if errors.Is(err, context.DeadlineExceeded) {
-			fmt.Fprintf(os.Stderr, "command is terminated by timeout: %d seconds\n", params.Timeout.Duration)
+			fmt.Fprintf(stderr, "command is terminated by timeout: %d seconds\n", int(params.Timeout.Duration.Seconds()))
 		}
 	}()
 	if err := cmd.Run(); err != nil {
@@ -2,6 +2,7 @@ package execute
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -36,6 +37,17 @@ func TestExecutor_Run(t *testing.T) {
 				Timeout: &Timeout{},
 			},
 		},
+		{
+			title: "timeout",
+			isErr: true,
+			params: &Params{
+				Script: "sleep 10",
+				Quiet:  true,
+				Timeout: &Timeout{
+					Duration: 100 * time.Millisecond,
+				},
+			},
+		},
 	}
 	exc := New()
 	for _, d := range data {
@@ -0,0 +1,59 @@
+package execute
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// prefixWriter prefixes each line with the prefix.
+// A line is written to the underlying writer at once, so lines of tasks run in parallel aren't mixed.
+type prefixWriter struct {
+	w      io.Writer
+	prefix []byte
+	buf    []byte
+	mutex  sync.Mutex
+}
+
+func newPrefixWriter(w io.Writer, prefix string) *prefixWriter {
+	return &prefixWriter{
+		w:      w,
+		prefix: []byte(prefix),
+	}
+}
+
+func (pw *prefixWriter) Write(p []byte) (int, error) {
+	pw.mutex.Lock()
+	defer pw.mutex.Unlock()
+	pw.buf = append(pw.buf, p...)
+	for {
+		i := bytes.IndexByte(pw.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if err := pw.writeLine(pw.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		pw.buf = pw.buf[i+1:]
+	}
+}
+
+// Flush writes the last line which doesn't end with a newline.
+func (pw *prefixWriter) Flush() error {
+	pw.mutex.Lock()
+	defer pw.mutex.Unlock()
+	if len(pw.buf) == 0 {
+		return nil
+	}
+	line := append(pw.buf, '\n')
+	pw.buf = nil
+	return pw.writeLine(line)
+}
+
+func (pw *prefixWriter) writeLine(line []byte) error {
+	b := make([]byte, 0, len(pw.prefix)+len(line))
+	b = append(b, pw.prefix...)
+	b = append(b, line...)
+	_, err := pw.w.Write(b)
+	return err
+}
@@ -0,0 +1,22 @@
+package execute
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_prefixWriter(t *testing.T) {
+	t.Parallel()
+	buf := &bytes.Buffer{}
+	pw := newPrefixWriter(buf, "[foo] ")
+	for _, s := range []string{"hello\nwor", "ld\n", "\nbar"} {
+		_, err := pw.Write([]byte(s))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, "[foo] hello\n[foo] world\n[foo] \n", buf.String())
+	require.NoError(t, pw.Flush())
+	assert.Equal(t, "[foo] hello\n[foo] world\n[foo] \n[foo] bar\n", buf.String())
+}
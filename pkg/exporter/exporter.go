@@ -0,0 +1,324 @@
+// Package exporter converts tasks into a Makefile or justfile.
+// Tasks which use features which make and just don't have, such as prompts, are exported as recipes which fail.
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+const (
+	FormatMake = "make"
+	FormatJust = "just"
+)
+
+// Result is the exported file.
+// Warnings are settings which are ignored or tasks which can't be exported.
+type Result struct {
+	Content  []byte
+	Warnings []string
+}
+
+func (r *Result) warn(format string, a ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, a...))
+}
+
+// recipe is the task converted into the representation which doesn't depend on the format.
+// If reason isn't empty, the task can't be exported.
+type recipe struct {
+	name   string
+	usage  string
+	short  string
+	deps   []string
+	params []param
+	// env is the task level environment variables which aren't set at the configuration level.
+	env    map[string]string
+	body   []segment
+	reason string
+}
+
+// param is a flag or a positional argument of the task.
+type param struct {
+	name       string
+	def        string
+	required   bool
+	inputEnvs  []string
+	scriptEnvs []string
+}
+
+// segment is a part of the script.
+// A segment is either a literal text, a reference to the parameter, or the standard output of the command.
+type segment struct {
+	text  string
+	param string
+	sh    string
+}
+
+// Export converts tasks of the configuration into the file of the format.
+// The configuration must be validated and set up in advance.
+func Export(cfg *domain.Config, format string) (*Result, error) {
+	result := &Result{}
+	if format != FormatMake && format != FormatJust {
+		return nil, errors.New("the export format must be either make or just: " + format)
+	}
+	if cfg.Before != "" || cfg.After != "" || cfg.Finally != "" {
+		result.warn("configuration level hooks aren't exported")
+	}
+	recipes := make([]*recipe, len(cfg.Tasks))
+	for i, task := range cfg.Tasks {
+		recipes[i] = newRecipe(cfg, &task, result)
+	}
+	switch format {
+	case FormatMake:
+		result.Content = renderMake(cfg.Environment, recipes)
+	case FormatJust:
+		result.Content = renderJust(cfg.Environment, recipes)
+	}
+	// Renderers also check names, so warnings are outputted after rendering.
+	for _, r := range recipes {
+		if r.reason != "" {
+			result.warn("the task %s can't be exported: %s", r.name, r.reason)
+		}
+	}
+	return result, nil
+}
+
+// unsupported returns the reason why the task can't be exported.
+func unsupported(task *domain.Task) string {
+	checks := []struct {
+		name string
+		used bool
+	}{
+		{"sub tasks", len(task.Tasks) != 0},
+		{"steps", len(task.Steps) != 0},
+		{"matrix", len(task.Matrix) != 0},
+		{"for_each", task.ForEach != ""},
+		{"retry", task.Retry.Attempts != 0},
+		{"if", task.If != ""},
+		{"env_files", len(task.EnvFiles) != 0},
+		{"shell", len(task.Shell) != 0},
+		{"tasks of configuration files in ancestor directories", task.Dir != ""},
+		{"prompt", slices.ContainsFunc(task.Flags, func(flag domain.Flag) bool {
+			return flag.Prompt.Type != ""
+		}) || slices.ContainsFunc(task.Args, func(arg domain.Arg) bool {
+			return arg.Prompt.Type != ""
+		})},
+	}
+	for _, c := range checks {
+		if c.used {
+			return c.name + " isn't supported"
+		}
+	}
+	return ""
+}
+
+func newRecipe(cfg *domain.Config, task *domain.Task, result *Result) *recipe {
+	r := &recipe{
+		name:  task.Name,
+		usage: task.Usage,
+		short: task.Short,
+		deps:  task.DependsOn,
+	}
+	if r.reason = unsupported(task); r.reason != "" {
+		return r
+	}
+	ignored := []string{}
+	if len(task.Require.Exec) != 0 || len(task.Require.Environment) != 0 {
+		ignored = append(ignored, "require")
+	}
+	if len(task.Sources) != 0 || len(task.Status) != 0 {
+		ignored = append(ignored, "sources and status")
+	}
+	if len(ignored) != 0 {
+		result.warn("task %s: %s isn't exported", task.Name, strings.Join(ignored, ", "))
+	}
+	for _, arg := range task.Args {
+		r.params = append(r.params, param{
+			name:       arg.Name,
+			def:        arg.Default,
+			required:   arg.Required,
+			inputEnvs:  arg.InputEnvs,
+			scriptEnvs: arg.ScriptEnvs,
+		})
+	}
+	for _, flag := range task.Flags {
+		def := flag.Default
+		if flag.Type == "bool" && def == "" {
+			def = "false"
+		}
+		r.params = append(r.params, param{
+			name:       flag.Name,
+			def:        def,
+			required:   flag.Required,
+			inputEnvs:  flag.InputEnvs,
+			scriptEnvs: flag.ScriptEnvs,
+		})
+	}
+	for k, v := range task.Environment {
+		if cv, ok := cfg.Environment[k]; ok && cv == v {
+			continue
+		}
+		if r.env == nil {
+			r.env = map[string]string{}
+		}
+		r.env[k] = v
+	}
+	res := &resolver{
+		params: r.params,
+		vars:   maps.Clone(cfg.Vars),
+	}
+	if res.vars == nil {
+		res.vars = map[string]domain.Var{}
+	}
+	maps.Copy(res.vars, task.Vars)
+	body, err := res.body(task)
+	if err != nil {
+		r.reason = err.Error()
+		return r
+	}
+	r.body = body
+	return r
+}
+
+type resolver struct {
+	params []param
+	vars   map[string]domain.Var
+}
+
+// body returns the script with hooks of the task.
+// The finally hook is run by the trap of EXIT.
+func (res *resolver) body(task *domain.Task) ([]segment, error) {
+	body := []segment{}
+	if task.Finally != "" {
+		segs, err := res.segments(task.Finally, nil)
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, segment{text: "trap '"})
+		for _, s := range segs {
+			s.text = strings.ReplaceAll(s.text, "'", `'\''`)
+			body = append(body, s)
+		}
+		body = append(body, segment{text: "' EXIT\n"})
+	}
+	for _, s := range []string{task.Before, task.Script, task.After} {
+		if s == "" {
+			continue
+		}
+		segs, err := res.segments(s, nil)
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, segs...)
+		body = append(body, segment{text: "\n"})
+	}
+	return body, nil
+}
+
+// segments converts the template into segments.
+// Only references to flags, arguments, and variables such as {{.name}} are supported.
+// stack is names of variables which are being resolved.
+func (res *resolver) segments(s string, stack []string) ([]segment, error) {
+	t, err := template.New("").Funcs(sprig.TxtFuncMap()).Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the template: %w", err)
+	}
+	if t.Tree == nil {
+		return nil, nil
+	}
+	segs := []segment{}
+	for _, node := range t.Root.Nodes {
+		switch n := node.(type) {
+		case *parse.TextNode:
+			segs = append(segs, segment{text: string(n.Text)})
+		case *parse.ActionNode:
+			arg := simpleAction(n)
+			switch a := arg.(type) {
+			case *parse.StringNode:
+				segs = append(segs, segment{text: a.Text})
+			case *parse.FieldNode:
+				ss, err := res.resolve(a.Ident[0], stack)
+				if err != nil {
+					return nil, err
+				}
+				segs = append(segs, ss...)
+			default:
+				return nil, errors.New("templates other than references to variables such as {{.name}} aren't supported")
+			}
+		default:
+			return nil, errors.New("templates other than references to variables such as {{.name}} aren't supported")
+		}
+	}
+	return segs, nil
+}
+
+// simpleAction returns the argument of the action such as {{.name}} and {{"{{"}}.
+// If the action is complicated, nil is returned.
+func simpleAction(n *parse.ActionNode) parse.Node {
+	if len(n.Pipe.Decl) != 0 || len(n.Pipe.Cmds) != 1 || len(n.Pipe.Cmds[0].Args) != 1 {
+		return nil
+	}
+	arg := n.Pipe.Cmds[0].Args[0]
+	if f, ok := arg.(*parse.FieldNode); ok && len(f.Ident) != 1 {
+		return nil
+	}
+	return arg
+}
+
+// resolve resolves the reference.
+// Flags and positional arguments take precedence over variables.
+// Variables can refer only other variables.
+func (res *resolver) resolve(name string, stack []string) ([]segment, error) {
+	if stack == nil && slices.ContainsFunc(res.params, func(p param) bool {
+		return p.name == name
+	}) {
+		return []segment{{param: name}}, nil
+	}
+	v, ok := res.vars[name]
+	if !ok || slices.Contains(stack, name) {
+		return nil, fmt.Errorf("the variable %s isn't defined", name)
+	}
+	if v.Sh != "" {
+		return []segment{{sh: v.Sh}}, nil
+	}
+	return res.segments(v.Value, append(stack, name))
+}
+
+// toIdentifier converts the flag name such as "dry-run" into the variable name such as "DRY_RUN".
+func toIdentifier(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// invalidDependency returns the reason why the recipe can't be exported if the name of the dependency is invalid.
+// For example, the task "db:migrate" of the included file with the namespace "db" isn't a valid target name of make.
+func invalidDependency(r *recipe, pattern *regexp.Regexp, kind string) string {
+	for _, dep := range r.deps {
+		if !pattern.MatchString(dep) {
+			return "the dependency " + dep + " isn't a valid " + kind + " name"
+		}
+	}
+	return ""
+}
+
+// failMessage is the message which the recipe of the task which can't be exported outputs.
+func failMessage(r *recipe) string {
+	return "the task " + r.name + " can't be exported from cmdx because " + r.reason + ". Please run cmdx " + r.name
+}
+
+// shellQuote quotes the string with single quotes for shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func sortedKeys(m map[string]string) []string {
+	return slices.Sorted(maps.Keys(m))
+}
@@ -0,0 +1,220 @@
+package exporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/prompt"
+)
+
+func testConfig() *domain.Config {
+	return &domain.Config{
+		Environment: map[string]string{"FOO": "foo"},
+		Vars: map[string]domain.Var{
+			"sha":     {Sh: "git rev-parse HEAD"},
+			"version": {Value: "v-{{.sha}}"},
+		},
+		Tasks: []domain.Task{
+			{
+				Name:  "build",
+				Short: "b",
+				Usage: "build the app",
+				Flags: []domain.Flag{
+					{Name: "goos", Default: "linux", InputEnvs: []string{"GOOS"}},
+					{Name: "dry-run", Type: "bool", ScriptEnvs: []string{"DRY_RUN"}},
+				},
+				Args:        []domain.Arg{{Name: "target", Required: true}},
+				Environment: map[string]string{"FOO": "foo", "CGO_ENABLED": "0"},
+				DependsOn:   []string{"gen"},
+				Hooks:       domain.Hooks{Finally: "rm -f tmp"},
+				Script:      "go build -ldflags '-X main.version={{.version}}' -o {{.target}} $PKG\necho {{\"{{\"}}done}}\n",
+			},
+			{
+				Name: "deploy",
+				Flags: []domain.Flag{
+					{Name: "env", Prompt: prompt.Prompt{Type: "select"}},
+				},
+				Script: "echo deploy",
+			},
+		},
+	}
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title    string
+		format   string
+		exp      string
+		warnings []string
+		isErr    bool
+	}{
+		{
+			title:  "make",
+			format: FormatMake,
+			exp: makeHeader + `
+export FOO := foo
+
+.PHONY: build b deploy
+
+build: export GOOS ?= linux
+build: export DRY_RUN = false
+build: export CGO_ENABLED = 0
+build: gen ## build the app
+	$(if $(TARGET),,$(error TARGET is required))
+	trap 'rm -f tmp' EXIT
+	go build -ldflags '-X main.version=v-$(shell git rev-parse HEAD)' -o $(TARGET) $$PKG
+	echo {{done}}
+b: build
+
+deploy:
+	@echo 'the task deploy can'\''t be exported from cmdx because prompt isn'\''t supported. Please run cmdx deploy' >&2; exit 1
+`,
+			warnings: []string{"the task deploy can't be exported: prompt isn't supported"},
+		},
+		{
+			title:  "just",
+			format: FormatJust,
+			exp: justHeader + `
+export FOO := 'foo'
+
+# build the app
+build target goos=env('GOOS', 'linux') dry_run='false': gen
+    #!/usr/bin/env bash
+    set -euo pipefail
+    export DRY_RUN={{quote(dry_run)}}
+    export CGO_ENABLED='0'
+    trap 'rm -f tmp' EXIT
+    go build -ldflags '-X main.version=v-{{` + "`git rev-parse HEAD`" + `}}' -o {{target}} $PKG
+    echo {{{{done}}
+
+alias b := build
+
+deploy:
+    @echo 'the task deploy can'\''t be exported from cmdx because prompt isn'\''t supported. Please run cmdx deploy' >&2; exit 1
+`,
+			warnings: []string{"the task deploy can't be exported: prompt isn't supported"},
+		},
+		{
+			title:  "unknown format",
+			format: "ninja",
+			isErr:  true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			result, err := Export(testConfig(), d.format)
+			if d.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, string(result.Content))
+			assert.Equal(t, d.warnings, result.Warnings)
+		})
+	}
+}
+
+func TestExport_namespace(t *testing.T) {
+	t.Parallel()
+	// The task of the included file with the namespace "db"
+	cfg := &domain.Config{
+		Tasks: []domain.Task{
+			{Name: "db:migrate", Script: "./migrate.sh"},
+			{Name: "up", DependsOn: []string{"db:migrate"}, Script: "docker compose up"},
+		},
+	}
+	data := []struct {
+		format string
+		exp    string
+	}{
+		{
+			format: FormatMake,
+			exp: makeHeader + `
+.PHONY: up
+
+# the task db:migrate can't be exported from cmdx because the task name isn't a valid target name. Please run cmdx db:migrate
+
+up:
+	@echo 'the task up can'\''t be exported from cmdx because the dependency db:migrate isn'\''t a valid target name. Please run cmdx up' >&2; exit 1
+`,
+		},
+		{
+			format: FormatJust,
+			exp: justHeader + `
+# the task db:migrate can't be exported from cmdx because the task name isn't a valid recipe name. Please run cmdx db:migrate
+
+up:
+    @echo 'the task up can'\''t be exported from cmdx because the dependency db:migrate isn'\''t a valid recipe name. Please run cmdx up' >&2; exit 1
+`,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.format, func(t *testing.T) {
+			t.Parallel()
+			result, err := Export(cfg, d.format)
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, string(result.Content))
+			assert.Len(t, result.Warnings, 2)
+		})
+	}
+}
+
+func Test_resolverSegments(t *testing.T) {
+	t.Parallel()
+	res := &resolver{
+		params: []param{{name: "name"}},
+		vars: map[string]domain.Var{
+			"a": {Value: "{{.b}}"},
+			"b": {Value: "{{.a}}"},
+			"c": {Value: "{{.name}}"},
+		},
+	}
+	data := []struct {
+		title  string
+		script string
+		exp    []segment
+		isErr  bool
+	}{
+		{
+			title:  "reference",
+			script: "echo {{.name}}",
+			exp:    []segment{{text: "echo "}, {param: "name"}},
+		},
+		{
+			title:  "control structure",
+			script: "{{if .name}}echo{{end}}",
+			isErr:  true,
+		},
+		{
+			title:  "function",
+			script: "{{.name | upper}}",
+			isErr:  true,
+		},
+		{
+			title:  "circular reference",
+			script: "{{.a}}",
+			isErr:  true,
+		},
+		{
+			title:  "variables can't refer flags",
+			script: "{{.c}}",
+			isErr:  true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			segs, err := res.segments(d.script, nil)
+			if d.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, segs)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package exporter
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var justNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+const justHeader = `# This file was generated by 'cmdx --export just'.
+# Flags and positional arguments of tasks are parameters of recipes such as 'just build true'.
+# Recipes are run by bash in one shell like cmdx.
+`
+
+// renderJust renders recipes as a justfile.
+//
+//   - Flags and positional arguments are converted into parameters.
+//     Required parameters are put before optional parameters because just requires it
+//   - environment is converted into exported variables
+//   - depends_on is converted into dependencies
+func renderJust(environment map[string]string, recipes []*recipe) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString(justHeader)
+	if len(environment) != 0 {
+		buf.WriteString("\n")
+		for _, k := range sortedKeys(environment) {
+			buf.WriteString("export " + k + " := " + justString(environment[k]) + "\n")
+		}
+	}
+	for _, r := range recipes {
+		buf.WriteString("\n")
+		renderJustRecipe(buf, r)
+	}
+	return buf.Bytes()
+}
+
+func renderJustRecipe(buf *bytes.Buffer, r *recipe) {
+	if !justNamePattern.MatchString(r.name) {
+		if r.reason == "" {
+			r.reason = "the task name isn't a valid recipe name"
+		}
+		buf.WriteString("# " + failMessage(r) + "\n")
+		return
+	}
+	if r.reason == "" {
+		r.reason = invalidDependency(r, justNamePattern, "recipe")
+	}
+	if r.reason == "" {
+		for _, p := range r.params {
+			if !justNamePattern.MatchString(toIdentifier(p.name)) {
+				r.reason = "the name " + p.name + " isn't a valid parameter name"
+			}
+		}
+	}
+	if r.usage != "" {
+		buf.WriteString("# " + r.usage + "\n")
+	}
+	if r.reason != "" {
+		buf.WriteString(r.name + ":\n")
+		buf.WriteString("    @echo " + shellQuote(justEscape(failMessage(r))) + " >&2; exit 1\n")
+		renderJustShort(buf, r)
+		return
+	}
+	params := slices.Clone(r.params)
+	slices.SortStableFunc(params, func(a, b param) int {
+		// Required parameters must be put before parameters with default values.
+		return justParamRank(a) - justParamRank(b)
+	})
+	line := r.name
+	for _, p := range params {
+		line += " " + justParam(p)
+	}
+	line += ":"
+	if len(r.deps) != 0 {
+		line += " " + strings.Join(r.deps, " ")
+	}
+	buf.WriteString(line + "\n")
+	lines := []string{"#!/usr/bin/env bash", "set -euo pipefail"}
+	for _, p := range r.params {
+		for _, e := range p.scriptEnvs {
+			lines = append(lines, "export "+e+"={{quote("+toIdentifier(p.name)+")}}")
+		}
+	}
+	for _, k := range sortedKeys(r.env) {
+		lines = append(lines, "export "+k+"="+shellQuote(justEscape(r.env[k])))
+	}
+	body := &strings.Builder{}
+	for _, s := range r.body {
+		switch {
+		case s.param != "":
+			body.WriteString("{{" + toIdentifier(s.param) + "}}")
+		case s.sh != "":
+			body.WriteString("{{`" + s.sh + "`}}")
+		default:
+			body.WriteString(justEscape(s.text))
+		}
+	}
+	lines = append(lines, strings.Split(strings.TrimRight(body.String(), "\n"), "\n")...)
+	for _, l := range lines {
+		if l == "" {
+			buf.WriteString("\n")
+			continue
+		}
+		buf.WriteString("    " + l + "\n")
+	}
+	renderJustShort(buf, r)
+}
+
+func renderJustShort(buf *bytes.Buffer, r *recipe) {
+	if r.short != "" {
+		buf.WriteString("\nalias " + r.short + " := " + r.name + "\n")
+	}
+}
+
+// justParam returns the parameter of the recipe.
+// If input_envs is set, the default value is read from environment variables.
+func justParam(p param) string {
+	name := toIdentifier(p.name)
+	if len(p.inputEnvs) == 0 {
+		if p.required {
+			return name
+		}
+		return name + "=" + justString(p.def)
+	}
+	var def string
+	if !p.required {
+		def = justString(p.def)
+	}
+	for i := len(p.inputEnvs) - 1; i >= 0; i-- {
+		if def == "" {
+			def = "env(" + justString(p.inputEnvs[i]) + ")"
+			continue
+		}
+		def = "env(" + justString(p.inputEnvs[i]) + ", " + def + ")"
+	}
+	return name + "=" + def
+}
+
+// justParamRank returns 0 if the parameter has no default value.
+func justParamRank(p param) int {
+	if p.required && len(p.inputEnvs) == 0 {
+		return 0
+	}
+	return 1
+}
+
+// justString returns the string literal of just.
+func justString(s string) string {
+	if !strings.ContainsAny(s, "'\n") {
+		return "'" + s + "'"
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`).Replace(s) + `"`
+}
+
+// justEscape escapes {{ so that just doesn't interpolate it.
+func justEscape(s string) string {
+	return strings.ReplaceAll(s, "{{", "{{{{")
+}
@@ -0,0 +1,146 @@
+package exporter
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var makeTargetPattern = regexp.MustCompile(`^[A-Za-z0-9_./-]+$`)
+
+const makeHeader = `# This file was generated by 'cmdx --export make'.
+# Flags and positional arguments of tasks are variables such as 'make build VERBOSE=true'.
+# Recipes are run by bash in one shell like cmdx.
+SHELL := bash
+.SHELLFLAGS := -euo pipefail -c
+.ONESHELL:
+`
+
+// renderMake renders recipes as a Makefile for GNU Make.
+//
+//   - Flags and positional arguments are converted into target-specific variables
+//   - environment is converted into exported variables
+//   - depends_on is converted into prerequisites
+//   - All targets are phony
+func renderMake(environment map[string]string, recipes []*recipe) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString(makeHeader)
+	if len(environment) != 0 {
+		buf.WriteString("\n")
+		for _, k := range sortedKeys(environment) {
+			buf.WriteString("export " + k + " := " + makeEscape(environment[k]) + "\n")
+		}
+	}
+	phony := []string{}
+	for _, r := range recipes {
+		if r.reason == "" && !makeTargetPattern.MatchString(r.name) {
+			r.reason = "the task name isn't a valid target name"
+		}
+		if r.reason == "" {
+			r.reason = invalidDependency(r, makeTargetPattern, "target")
+		}
+		if makeTargetPattern.MatchString(r.name) {
+			phony = append(phony, r.name)
+		}
+		if r.short != "" {
+			phony = append(phony, r.short)
+		}
+	}
+	buf.WriteString("\n.PHONY: " + strings.Join(phony, " ") + "\n")
+	for _, r := range recipes {
+		buf.WriteString("\n")
+		renderMakeRecipe(buf, r)
+	}
+	return buf.Bytes()
+}
+
+func renderMakeRecipe(buf *bytes.Buffer, r *recipe) {
+	if !makeTargetPattern.MatchString(r.name) {
+		buf.WriteString("# " + failMessage(r) + "\n")
+		return
+	}
+	rule := r.name + ":"
+	if r.reason == "" && len(r.deps) != 0 {
+		rule += " " + strings.Join(r.deps, " ")
+	}
+	if r.usage != "" {
+		rule += " ## " + r.usage
+	}
+	if r.reason != "" {
+		buf.WriteString(rule + "\n")
+		buf.WriteString("\t@echo " + shellQuote(makeEscape(failMessage(r))) + " >&2; exit 1\n")
+		renderMakeShort(buf, r)
+		return
+	}
+	names := map[string]string{}
+	required := []string{}
+	for _, p := range r.params {
+		name := makeVarName(p)
+		names[p.name] = name
+		export := ""
+		if len(p.inputEnvs) != 0 || slices.Contains(p.scriptEnvs, name) {
+			// The variable from the environment variable is passed to the script.
+			export = "export "
+		}
+		op := "="
+		if len(p.inputEnvs) != 0 {
+			// Environment variables can override the default value like input_envs.
+			op = "?="
+		}
+		if p.def != "" || export != "" {
+			buf.WriteString(r.name + ": " + export + name + " " + op + " " + makeEscape(p.def) + "\n")
+		}
+		for _, e := range p.scriptEnvs {
+			if e != name {
+				buf.WriteString(r.name + ": export " + e + " = $(" + name + ")\n")
+			}
+		}
+		if p.required {
+			required = append(required, name)
+		}
+	}
+	for _, k := range sortedKeys(r.env) {
+		buf.WriteString(r.name + ": export " + k + " = " + makeEscape(r.env[k]) + "\n")
+	}
+	buf.WriteString(rule + "\n")
+	for _, name := range required {
+		buf.WriteString("\t$(if $(" + name + "),,$(error " + name + " is required))\n")
+	}
+	body := &strings.Builder{}
+	for _, s := range r.body {
+		switch {
+		case s.param != "":
+			body.WriteString("$(" + names[s.param] + ")")
+		case s.sh != "":
+			body.WriteString("$(shell " + makeEscape(s.sh) + ")")
+		default:
+			body.WriteString(makeEscape(s.text))
+		}
+	}
+	for line := range strings.SplitSeq(strings.TrimRight(body.String(), "\n"), "\n") {
+		buf.WriteString("\t" + line + "\n")
+	}
+	renderMakeShort(buf, r)
+}
+
+// renderMakeShort renders the alias of the task.
+func renderMakeShort(buf *bytes.Buffer, r *recipe) {
+	if r.short != "" {
+		buf.WriteString(r.short + ": " + r.name + "\n")
+	}
+}
+
+// makeVarName returns the variable name of the parameter.
+// If input_envs is set, the first environment variable is used so that the environment variable is passed to the variable.
+func makeVarName(p param) string {
+	if len(p.inputEnvs) != 0 {
+		return p.inputEnvs[0]
+	}
+	return strings.ToUpper(toIdentifier(p.name))
+}
+
+// makeEscape escapes $ so that make doesn't expand it.
+func makeEscape(s string) string {
+	return strings.ReplaceAll(s, "$", "$$")
+}
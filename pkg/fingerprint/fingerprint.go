@@ -0,0 +1,110 @@
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Checker checks whether the task is up to date.
+// The fingerprint of the last successful run is stored in the state directory.
+type Checker struct {
+	stateDir string
+}
+
+func New(stateDir string) *Checker {
+	return &Checker{
+		stateDir: stateDir,
+	}
+}
+
+// Sum returns the fingerprint of the task.
+// The fingerprint is computed from the content of source files and the given seed such as the script.
+func Sum(dir string, sources []string, seed string) (string, error) {
+	files := []string{}
+	for _, source := range sources {
+		matches, err := Glob(dir, source)
+		if err != nil {
+			return "", err
+		}
+		files = append(files, matches...)
+	}
+	slices.Sort(files)
+	files = slices.Compact(files)
+
+	h := sha256.New()
+	h.Write([]byte(seed))
+	for _, file := range files {
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return "", fmt.Errorf("failed to get the relative path of the source file: %w", err)
+		}
+		h.Write([]byte{0})
+		h.Write([]byte(filepath.ToSlash(rel)))
+		h.Write([]byte{0})
+		if err := hashFile(h, file); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(w io.Writer, p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return fmt.Errorf("failed to open the source file %s: %w", p, err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(w, f); err != nil {
+		return fmt.Errorf("failed to read the source file %s: %w", p, err)
+	}
+	return nil
+}
+
+func (checker *Checker) path(key string) string {
+	return filepath.Join(checker.stateDir, "fingerprints", url.PathEscape(key))
+}
+
+// IsUpToDate returns true if the fingerprint equals the fingerprint of the last successful run
+// and all generated files exist.
+func (checker *Checker) IsUpToDate(key, sum, dir string, generates []string) (bool, error) {
+	b, err := os.ReadFile(checker.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read the fingerprint: %w", err)
+	}
+	if strings.TrimSpace(string(b)) != sum {
+		return false, nil
+	}
+	for _, generate := range generates {
+		files, err := Glob(dir, generate)
+		if err != nil {
+			return false, err
+		}
+		if len(files) == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Save stores the fingerprint of the successful run.
+func (checker *Checker) Save(key, sum string) error {
+	p := checker.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil { //nolint:mnd
+		return fmt.Errorf("failed to create the state directory: %w", err)
+	}
+	if err := os.WriteFile(p, []byte(sum+"\n"), 0o644); err != nil { //nolint:gosec,mnd
+		return fmt.Errorf("failed to write the fingerprint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_IsUpToDate(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFiles(t, dir, "main.go")
+	checker := New(filepath.Join(dir, ".cmdx"))
+	sources := []string{"*.go"}
+	generates := []string{"bin/*"}
+
+	sum, err := Sum(dir, sources, "go build")
+	require.NoError(t, err)
+
+	upToDate, err := checker.IsUpToDate("admin build", sum, dir, generates)
+	require.NoError(t, err)
+	assert.False(t, upToDate, "the fingerprint isn't saved")
+
+	require.NoError(t, checker.Save("admin build", sum))
+	upToDate, err = checker.IsUpToDate("admin build", sum, dir, generates)
+	require.NoError(t, err)
+	assert.False(t, upToDate, "generated files don't exist")
+
+	writeFiles(t, dir, "bin/app")
+	upToDate, err = checker.IsUpToDate("admin build", sum, dir, generates)
+	require.NoError(t, err)
+	assert.True(t, upToDate)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("changed"), 0o644))
+	newSum, err := Sum(dir, sources, "go build")
+	require.NoError(t, err)
+	assert.NotEqual(t, sum, newSum)
+	upToDate, err = checker.IsUpToDate("admin build", newSum, dir, generates)
+	require.NoError(t, err)
+	assert.False(t, upToDate, "source files are changed")
+
+	seedSum, err := Sum(dir, sources, "go build -v")
+	require.NoError(t, err)
+	assert.NotEqual(t, newSum, seedSum)
+}
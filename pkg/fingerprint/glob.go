@@ -0,0 +1,93 @@
+package fingerprint
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Glob returns files matching the pattern.
+// The pattern is relative to dir, and "**" matches zero or more directories.
+// Directories aren't included in the result.
+func Glob(dir, pattern string) ([]string, error) {
+	pattern = filepath.ToSlash(pattern)
+	if filepath.IsAbs(pattern) {
+		rel, err := filepath.Rel(dir, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert the absolute path to the relative path: %w", err)
+		}
+		pattern = filepath.ToSlash(rel)
+	}
+	if !strings.Contains(pattern, "**") {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("failed to search files: %s: %w", pattern, err)
+		}
+		files := make([]string, 0, len(matches))
+		for _, match := range matches {
+			if isFile(match) {
+				files = append(files, match)
+			}
+		}
+		return files, nil
+	}
+
+	segments := strings.Split(pattern, "/")
+	files := []string{}
+	if err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		f, err := match(segments, strings.Split(filepath.ToSlash(rel), "/"))
+		if err != nil {
+			return err
+		}
+		if f {
+			files = append(files, p)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to search files: %s: %w", pattern, err)
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func match(patterns, names []string) (bool, error) {
+	for len(patterns) != 0 {
+		if patterns[0] == "**" {
+			for i := range len(names) + 1 {
+				f, err := match(patterns[1:], names[i:])
+				if err != nil || f {
+					return f, err
+				}
+			}
+			return false, nil
+		}
+		if len(names) == 0 {
+			return false, nil
+		}
+		f, err := filepath.Match(patterns[0], names[0])
+		if err != nil || !f {
+			return false, err
+		}
+		patterns = patterns[1:]
+		names = names[1:]
+	}
+	return len(names) == 0, nil
+}
+
+func isFile(p string) bool {
+	info, err := os.Stat(p)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,70 @@
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files ...string) {
+	t.Helper()
+	for _, file := range files {
+		p := filepath.Join(dir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(file), 0o644))
+	}
+}
+
+func TestGlob(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeFiles(t, dir, "main.go", "README.md", "pkg/foo/foo.go", "pkg/bar.go", "pkg/foo/foo.txt")
+	data := []struct {
+		title   string
+		pattern string
+		exp     []string
+	}{
+		{
+			title:   "simple",
+			pattern: "*.go",
+			exp:     []string{"main.go"},
+		},
+		{
+			title:   "directories are excluded",
+			pattern: "pkg/*",
+			exp:     []string{"pkg/bar.go"},
+		},
+		{
+			title:   "double star",
+			pattern: "**/*.go",
+			exp:     []string{"main.go", "pkg/bar.go", "pkg/foo/foo.go"},
+		},
+		{
+			title:   "double star in the middle",
+			pattern: "pkg/**/*.txt",
+			exp:     []string{"pkg/foo/foo.txt"},
+		},
+		{
+			title:   "no match",
+			pattern: "**/*.yaml",
+			exp:     []string{},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			files, err := Glob(dir, d.pattern)
+			require.NoError(t, err)
+			rels := make([]string, len(files))
+			for i, file := range files {
+				rel, err := filepath.Rel(dir, file)
+				require.NoError(t, err)
+				rels[i] = filepath.ToSlash(rel)
+			}
+			assert.Equal(t, d.exp, rels)
+		})
+	}
+}
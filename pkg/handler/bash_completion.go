@@ -44,14 +44,26 @@ func rootBashCompletion(flags *LDFlags, args []string) func(c *cli.Context) {
 			}
 		}
 
-		if err := cfgClient.Read(cfgFilePath, &cfg); err != nil {
+		if err := cfgClient.Load(cfgFilePath, &cfg); err != nil {
 			fmt.Println(err)
 			return
 		}
-		if err := validate.Config(&cfg); err != nil {
+		if err := cfgClient.LoadInherited(cfgFilePath, cfgFileName, &cfg); err != nil {
+			fmt.Println(err)
+			return
+		}
+		if err := mergeGlobalConfig(cfgClient, &cfg); err != nil {
+			fmt.Println(err)
+			return
+		}
+		if err := config.ResolveExtends(cfg.Tasks); err != nil {
 			fmt.Println(fmt.Errorf("please fix the configuration file: %w", err))
 			return
 		}
+		if err := validate.Config(&cfg); err != nil {
+			fmt.Println(fmt.Errorf("please fix the configuration file:\n%w", err))
+			return
+		}
 
 		if err := setupConfig(&cfg); err != nil {
 			fmt.Println(err)
@@ -60,7 +72,7 @@ func rootBashCompletion(flags *LDFlags, args []string) func(c *cli.Context) {
 
 		app := cli.NewApp()
 		setupApp(app, flags)
-		updateAppWithConfig(app, &cfg, &domain.GlobalFlags{})
+		updateAppWithConfig(app, &cfg, &domain.GlobalFlags{}, nil, nil)
 		if err := app.Run(args); err != nil {
 			fmt.Println(err)
 			return
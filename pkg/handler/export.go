@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/exporter"
+)
+
+// exportConfig converts tasks into a Makefile or justfile.
+// The file is outputted to the standard output and warnings are outputted to the standard error.
+func exportConfig(cfg *domain.Config, format string) error {
+	result, err := exporter.Export(cfg, format)
+	if err != nil {
+		return err
+	}
+	for _, w := range result.Warnings {
+		fmt.Fprintln(os.Stderr, "WARN: "+w)
+	}
+	if _, err := os.Stdout.Write(result.Content); err != nil {
+		return fmt.Errorf("failed to output the file: %w", err)
+	}
+	return nil
+}
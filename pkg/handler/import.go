@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/importer"
+	"github.com/suzuki-shunsuke/cmdx/pkg/validate"
+)
+
+// importConfig converts the file of another task runner into the configuration of cmdx.
+// The configuration is outputted to the standard output and warnings are outputted to the standard error.
+func importConfig(p string) error {
+	result, err := importer.Import(p)
+	if err != nil {
+		return err
+	}
+	for _, w := range result.Warnings {
+		fmt.Fprintln(os.Stderr, "WARN: "+w)
+	}
+	if err := validate.Config(result.Config); err != nil {
+		fmt.Fprintf(os.Stderr, "WARN: the converted configuration is invalid, so please fix it:\n%v\n", err)
+	}
+	b, err := importer.Marshal(result.Config)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stdout.Write(b); err != nil {
+		return fmt.Errorf("failed to output the configuration: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/config"
+	"github.com/suzuki-shunsuke/cmdx/pkg/scaffold"
+)
+
+// initConfig creates the configuration file with starter tasks of the detected projects.
+// If no project is detected, the default template is used.
+// If the configuration file already exists, do nothing.
+func initConfig(cfgClient *config.Client, cfgFilePath string, yes bool) error {
+	if _, err := os.Stat(cfgFilePath); err == nil {
+		return nil
+	}
+	if err := config.CheckCreatable(cfgFilePath); err != nil {
+		return err
+	}
+	projects := scaffold.Detect(filepath.Dir(cfgFilePath))
+	if len(projects) == 0 {
+		return cfgClient.Create(cfgFilePath)
+	}
+	selected, err := scaffold.Select(scaffold.Candidates(projects), yes)
+	if err != nil {
+		return err
+	}
+	content, err := scaffold.Render(projects, selected)
+	if err != nil {
+		return fmt.Errorf("failed to render the configuration file: %w", err)
+	}
+	return cfgClient.CreateWithContent(cfgFilePath, content)
+}
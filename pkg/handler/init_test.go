@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/config"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+func Test_initConfig(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		name  string
+		isErr bool
+	}{
+		{
+			title: "yaml",
+			name:  ".cmdx.yaml",
+		},
+		{
+			title: "json isn't supported",
+			name:  ".cmdx.json",
+			isErr: true,
+		},
+		{
+			title: "jsonnet isn't supported",
+			name:  "cmdx.jsonnet",
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			p := filepath.Join(t.TempDir(), d.name)
+			err := initConfig(config.New(), p, true)
+			if d.isErr {
+				require.Error(t, err)
+				assert.NoFileExists(t, p)
+				return
+			}
+			require.NoError(t, err)
+			cfg := &domain.Config{}
+			require.NoError(t, config.New().Load(p, cfg))
+		})
+	}
+}
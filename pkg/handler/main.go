@@ -6,14 +6,18 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"syscall"
 
 	"github.com/suzuki-shunsuke/cmdx/pkg/config"
 	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/scheduler"
 	action "github.com/suzuki-shunsuke/cmdx/pkg/task-action"
 	"github.com/suzuki-shunsuke/cmdx/pkg/tmpl"
 	"github.com/suzuki-shunsuke/cmdx/pkg/validate"
+	"github.com/suzuki-shunsuke/cmdx/pkg/variable"
 	"github.com/urfave/cli/v2"
 )
 
@@ -81,12 +85,15 @@ func mainAction(flags *LDFlags, args []string) func(*cli.Context) error {
 		cfgClient := config.New()
 		if initFlag {
 			if cfgFilePath != "" {
-				return cfgClient.Create(cfgFilePath)
+				return initConfig(cfgClient, cfgFilePath, c.Bool("yes"))
 			}
 			if cfgFileName != "" {
-				return cfgClient.Create(cfgFileName)
+				return initConfig(cfgClient, cfgFileName, c.Bool("yes"))
 			}
-			return cfgClient.Create(".cmdx.yaml")
+			return initConfig(cfgClient, ".cmdx.yaml", c.Bool("yes"))
+		}
+		if p := c.String("import"); p != "" {
+			return importConfig(p)
 		}
 
 		if cfgFilePath == "" {
@@ -107,17 +114,43 @@ func mainAction(flags *LDFlags, args []string) func(*cli.Context) error {
 			}
 		}
 
-		if err := cfgClient.Read(cfgFilePath, &cfg); err != nil {
+		if c.Bool("migrate") {
+			return migrateConfig(cfgClient, cfgFilePath)
+		}
+
+		if err := cfgClient.Load(cfgFilePath, &cfg); err != nil {
 			return err
 		}
-		if err := validate.Config(&cfg); err != nil {
+		exportFormat := c.String("export")
+		// Tasks of inherited configuration files and the global configuration file aren't exported.
+		if exportFormat == "" {
+			if err := cfgClient.LoadInherited(cfgFilePath, cfgFileName, &cfg); err != nil {
+				return err
+			}
+			if err := mergeGlobalConfig(cfgClient, &cfg); err != nil {
+				return err
+			}
+		}
+		if err := config.ResolveExtends(cfg.Tasks); err != nil {
 			return fmt.Errorf("please fix the configuration file: %w", err)
 		}
+		if err := validate.Config(&cfg); err != nil {
+			return fmt.Errorf("please fix the configuration file:\n%w", err)
+		}
+
+		profile := c.String("profile")
+		if err := config.ApplyProfile(&cfg, profile); err != nil {
+			return err
+		}
 
 		if err := setupConfig(&cfg); err != nil {
 			return err
 		}
 
+		if exportFormat != "" {
+			return exportConfig(&cfg, exportFormat)
+		}
+
 		if listFlag {
 			arr := make([]string, len(cfg.Tasks))
 			for i, task := range cfg.Tasks {
@@ -126,6 +159,9 @@ func mainAction(flags *LDFlags, args []string) func(*cli.Context) error {
 					name += ", " + task.Short
 				}
 				arr[i] = name + " - " + task.Usage
+				if task.Source != cfgFilePath {
+					arr[i] += " (" + relPath(task.Source) + ")"
+				}
 			}
 			fmt.Println(strings.Join(arr, "\n"))
 			return nil
@@ -135,21 +171,99 @@ func mainAction(flags *LDFlags, args []string) func(*cli.Context) error {
 		setupApp(app, flags)
 		if workingDirFlag == "" {
 			workingDirFlag = filepath.Dir(cfgFilePath)
+		} else {
+			// --working-dir takes precedence over the directories of configuration files in ancestor directories.
+			for i := range cfg.Tasks {
+				cfg.Tasks[i].Dir = ""
+			}
 		}
 		var quiet *bool
 		if c.IsSet("quiet") {
 			q := c.Bool("quiet")
 			quiet = &q
 		}
-		updateAppWithConfig(app, &cfg, &domain.GlobalFlags{
+		gFlags := &domain.GlobalFlags{
 			DryRun:     c.Bool("dry-run"),
 			Quiet:      quiet,
 			WorkingDir: workingDirFlag,
-		})
+			Force:      c.Bool("force"),
+			Only:       c.StringSlice("only"),
+			ConfigDir:  filepath.Dir(cfgFilePath),
+			Profile:    profile,
+		}
+		jobs := cfg.Concurrency
+		if c.IsSet("jobs") {
+			jobs = c.Int("jobs")
+		}
+		if gFlags.DryRun && profile != "" {
+			// The header isn't outputted without profiles to keep the output of the dry run as it was.
+			fmt.Fprintln(os.Stderr, "# dry run (profile: "+profile+")")
+		}
+		evaluator := variable.New(cfg.Vars, workingDirFlag)
+		updateAppWithConfig(app, &cfg, gFlags, newScheduler(flags, &cfg, gFlags, jobs, evaluator), evaluator)
 		return app.RunContext(c.Context, args)
 	}
 }
 
+func migrateConfig(cfgClient *config.Client, cfgFilePath string) error {
+	changed, err := cfgClient.Migrate(cfgFilePath)
+	if err != nil {
+		return err
+	}
+	if changed {
+		fmt.Fprintf(os.Stderr, "the configuration file is migrated to the version %d: %s\n", domain.ConfigVersion, cfgFilePath)
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "the configuration file is already the latest version %d: %s\n", domain.ConfigVersion, cfgFilePath)
+	return nil
+}
+
+// relPath returns the path relative to the current directory.
+// If the relative path can't be gotten, the path is returned as is.
+func relPath(p string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return p
+	}
+	rel, err := filepath.Rel(wd, p)
+	if err != nil {
+		return p
+	}
+	return rel
+}
+
+// mergeGlobalConfig merges the user level configuration file into cfg if it exists.
+func mergeGlobalConfig(cfgClient *config.Client, cfg *domain.Config) error {
+	p, err := cfgClient.GetGlobalFilePath()
+	if err != nil || p == "" {
+		return err
+	}
+	global := &domain.Config{}
+	if err := cfgClient.LoadGlobal(p, global); err != nil {
+		return err
+	}
+	config.Merge(cfg, global)
+	return nil
+}
+
+// newScheduler returns a scheduler which runs a dependency in-process.
+// The dependency is run by a new app so that flags and positional arguments are handled in the same way as the command line.
+func newScheduler(flags *LDFlags, cfg *domain.Config, gFlags *domain.GlobalFlags, jobs int, evaluator *variable.Evaluator) *scheduler.Scheduler {
+	var sched *scheduler.Scheduler
+	sched = scheduler.New(func(ctx context.Context, args []string) error {
+		app := cli.NewApp()
+		setupApp(app, flags)
+		// Return the error to the caller instead of exiting the process.
+		app.ExitErrHandler = func(*cli.Context, error) {}
+		// The configuration level hooks are run only around the task run from the command line.
+		nested := *cfg
+		nested.Hooks = domain.Hooks{}
+		updateAppWithConfig(app, &nested, gFlags, sched, evaluator)
+		return app.RunContext(ctx, append([]string{app.Name}, args...))
+	}, jobs)
+	return sched
+}
+
 func setupApp(app *cli.App, flags *LDFlags) {
 	app.Name = "cmdx"
 	app.Version = flags.AppVersion()
@@ -182,7 +296,24 @@ func setupApp(app *cli.App, flags *LDFlags) {
 		&cli.BoolFlag{
 			Name:    "init",
 			Aliases: []string{"i"},
-			Usage:   "create the configuration file",
+			Usage:   "create the configuration file. Starter tasks are proposed based on the project files such as go.mod",
+		},
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "accept the default starter tasks of --init without asking",
+		},
+		&cli.StringFlag{
+			Name:  "import",
+			Usage: "convert Makefile, package.json, or Taskfile.yml into the configuration of cmdx and output it",
+		},
+		&cli.StringFlag{
+			Name:  "export",
+			Usage: "convert tasks into a Makefile or justfile and output it. The value must be either 'make' or 'just'",
+		},
+		&cli.BoolFlag{
+			Name:  "migrate",
+			Usage: "convert the configuration file into the latest version",
 		},
 		&cli.BoolFlag{
 			Name:    "list",
@@ -194,11 +325,30 @@ func setupApp(app *cli.App, flags *LDFlags) {
 			Aliases: []string{"q"},
 			Usage:   "don't output the executed command",
 		},
+		&cli.StringFlag{
+			Name:    "profile",
+			Usage:   "the profile which overrides environment variables, default values of flags, input_envs, and timeout",
+			EnvVars: []string{"CMDX_PROFILE"},
+		},
 		&cli.BoolFlag{
 			Name:    "dry-run",
 			Aliases: []string{"d"},
 			Usage:   "output the script but don't run it actually",
 		},
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "run tasks even if they are up to date",
+		},
+		&cli.StringSliceFlag{
+			Name:  "only",
+			Usage: "run only matrix combinations matching key=value. This option can be set multiple times",
+		},
+		&cli.IntFlag{
+			Name:    "jobs",
+			Aliases: []string{"j"},
+			Usage:   "the maximum number of tasks run in parallel. By default, the configuration 'concurrency' is used",
+			EnvVars: []string{"CMDX_JOBS"},
+		},
 	}
 }
 
@@ -210,6 +360,10 @@ func newFlag(flag domain.Flag) cli.Flag {
 			Usage:   flag.Usage,
 			EnvVars: flag.InputEnvs,
 		}
+		// The default value is validated in advance, and it can be set by profiles.
+		if v, err := strconv.ParseBool(flag.Default); err == nil {
+			f.Value = v
+		}
 		if flag.Short != "" {
 			f.Aliases = []string{flag.Short}
 		}
@@ -279,7 +433,9 @@ REQUIRED ENVIRONMENT VARIABLES:
 	return txt
 }
 
-func convertTaskToCommand(task domain.Task, gFlags *domain.GlobalFlags) *cli.Command {
+func convertTaskToCommand(
+	task domain.Task, gFlags *domain.GlobalFlags, sched *scheduler.Scheduler, cfgHooks domain.Hooks, evaluator *variable.Evaluator,
+) *cli.Command {
 	help := getHelp(cli.CommandHelpTemplate, task)
 	if !strings.HasSuffix(help, "\n") {
 		help += "\n"
@@ -288,7 +444,7 @@ func convertTaskToCommand(task domain.Task, gFlags *domain.GlobalFlags) *cli.Com
 	if len(task.Tasks) != 0 {
 		tasks := make([]*cli.Command, len(task.Tasks))
 		for i, s := range task.Tasks {
-			tasks[i] = convertTaskToCommand(s, gFlags)
+			tasks[i] = convertTaskToCommand(s, gFlags, sched, cfgHooks, evaluator)
 		}
 		aliases := []string{}
 		if task.Short != "" {
@@ -332,15 +488,25 @@ func convertTaskToCommand(task domain.Task, gFlags *domain.GlobalFlags) *cli.Com
 		Usage:              task.Usage,
 		Description:        task.Description,
 		Flags:              flags,
-		Action:             action.NewCommandAction(task, gFlags, scriptEnvs),
+		Action:             action.NewCommandAction(task, gFlags, scriptEnvs, sched, cfgHooks, evaluator),
 		CustomHelpTemplate: help,
 	}
 }
 
-func updateAppWithConfig(app *cli.App, cfg *domain.Config, gFlags *domain.GlobalFlags) {
+func updateAppWithConfig(
+	app *cli.App, cfg *domain.Config, gFlags *domain.GlobalFlags, sched *scheduler.Scheduler, evaluator *variable.Evaluator,
+) {
 	cmds := make([]*cli.Command, len(cfg.Tasks))
 	for i, task := range cfg.Tasks {
-		cmds[i] = convertTaskToCommand(task, gFlags)
+		g := gFlags
+		if task.Dir != "" {
+			// The task is run in the directory of the configuration file where the task is defined.
+			c := *gFlags
+			c.WorkingDir = task.Dir
+			c.ConfigDir = task.Dir
+			g = &c
+		}
+		cmds[i] = convertTaskToCommand(task, g, sched, cfg.Hooks, evaluator)
 	}
 	app.Commands = cmds
 }
@@ -383,6 +549,9 @@ func setupTask(task, base *domain.Task) error {
 		}
 	}
 
+	// Task level env files take precedence over configuration level env files.
+	task.EnvFiles = append(slices.Clone(base.EnvFiles), task.EnvFiles...)
+
 	if task.Timeout.Duration == 0 {
 		if base.Timeout.Duration == 0 {
 			task.Timeout.Duration = defaultTimeout
@@ -473,6 +642,7 @@ func setupConfig(cfg *domain.Config) error {
 		ScriptEnvs:  cfg.ScriptEnvs,
 		Environment: cfg.Environment,
 		Timeout:     cfg.Timeout,
+		EnvFiles:    cfg.EnvFiles,
 	}
 	for i, task := range cfg.Tasks {
 		if err := setupTask(&task, base); err != nil {
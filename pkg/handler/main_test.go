@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -60,6 +61,18 @@ func Test_newFlag(t *testing.T) {
 				Aliases: []string{"f"},
 			},
 		},
+		{
+			title: "bool with the default value",
+			flag: domain.Flag{
+				Name:    valFoo,
+				Type:    "bool",
+				Default: "true",
+			},
+			exp: &cli.BoolFlag{
+				Name:  valFoo,
+				Value: true,
+			},
+		},
 		{
 			title: "string",
 			flag: domain.Flag{
@@ -175,7 +188,7 @@ ARGUMENTS:
 	}
 	for _, d := range data {
 		t.Run(d.title, func(t *testing.T) {
-			cmd := convertTaskToCommand(d.task, &domain.GlobalFlags{})
+			cmd := convertTaskToCommand(d.task, &domain.GlobalFlags{}, nil, domain.Hooks{}, nil)
 			assert.Equal(t, d.exp.Name, cmd.Name)
 			assert.Equal(t, d.exp.Aliases, cmd.Aliases)
 			assert.Equal(t, d.exp.Usage, cmd.Usage)
@@ -362,7 +375,7 @@ func Test_updateAppWithConfig(t *testing.T) {
 	for _, d := range data {
 		t.Run(d.title, func(_ *testing.T) {
 			app := cli.NewApp()
-			updateAppWithConfig(app, d.cfg, &domain.GlobalFlags{WorkingDir: "/tmp"})
+			updateAppWithConfig(app, d.cfg, &domain.GlobalFlags{WorkingDir: "/tmp"}, nil, nil)
 		})
 	}
 }
@@ -384,3 +397,62 @@ func Test_getHelp(t *testing.T) {
 		assert.Equal(t, d.exp, getHelp(d.txt, d.task))
 	}
 }
+
+func Test_newScheduler(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title  string
+		dryRun bool
+		exp    bool
+	}{
+		{
+			title: "the called task is run with flags and args",
+			exp:   true,
+		},
+		{
+			title:  "dry run is passed to the called task",
+			dryRun: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			cfg := &domain.Config{
+				Tasks: []domain.Task{
+					{
+						Name: "release",
+						Steps: []domain.Step{
+							{
+								Task:  "deploy",
+								Flags: map[string]string{"env": "prod"},
+								Args:  []string{"web"},
+							},
+						},
+					},
+					{
+						Name:   "deploy",
+						Flags:  []domain.Flag{{Name: "env"}},
+						Args:   []domain.Arg{{Name: "service"}},
+						Script: "touch {{.env}}-{{.service}}",
+					},
+				},
+			}
+			require.NoError(t, setupConfig(cfg))
+			quiet := true
+			gFlags := &domain.GlobalFlags{
+				WorkingDir: dir,
+				ConfigDir:  dir,
+				DryRun:     d.dryRun,
+				Quiet:      &quiet,
+			}
+			sched := newScheduler(&LDFlags{}, cfg, gFlags, 1, nil)
+			require.NoError(t, sched.RunTask(t.Context(), []string{"release"}))
+			if d.exp {
+				assert.FileExists(t, filepath.Join(dir, "prod-web"))
+				return
+			}
+			assert.NoFileExists(t, filepath.Join(dir, "prod-web"))
+		})
+	}
+}
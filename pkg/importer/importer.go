@@ -0,0 +1,99 @@
+// Package importer converts tasks of other task runners into cmdx tasks.
+// Makefile, package.json scripts, and Taskfile.yml of go-task are supported.
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"go.yaml.in/yaml/v3"
+)
+
+// Result is the converted configuration.
+// Warnings are constructs which can't be converted.
+type Result struct {
+	Config   *domain.Config
+	Warnings []string
+}
+
+func (r *Result) warn(format string, a ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, a...))
+}
+
+// Import converts the file into the configuration of cmdx.
+// The file type is decided by the file name.
+func Import(p string) (*Result, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the file %s: %w", p, err)
+	}
+	var result *Result
+	switch name := strings.ToLower(filepath.Base(p)); {
+	case name == "package.json":
+		result, err = importNPM(b)
+	case name == "taskfile.yml" || name == "taskfile.yaml" || name == "taskfile.dist.yml" || name == "taskfile.dist.yaml":
+		result, err = importTaskfile(b)
+	case name == "makefile" || name == "gnumakefile" || filepath.Ext(name) == ".mk":
+		result, err = importMakefile(b)
+	default:
+		return nil, errors.New("the file isn't supported. Makefile, package.json, and Taskfile.yml are supported: " + p)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to import %s: %w", p, err)
+	}
+	result.Config.Version = domain.ConfigVersion
+	return result, nil
+}
+
+// Marshal converts the configuration into YAML.
+// Empty fields are omitted and fields are outputted in the order of domain.Config.
+func Marshal(cfg *domain.Config) ([]byte, error) {
+	// JSON tags of domain.Config have omitempty, so the configuration is converted via JSON.
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the configuration as JSON: %w", err)
+	}
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(b, node); err != nil {
+		return nil, fmt.Errorf("failed to convert JSON into YAML: %w", err)
+	}
+	clearStyle(node)
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2) //nolint:mnd
+	if err := enc.Encode(node); err != nil {
+		return nil, fmt.Errorf("failed to encode the configuration as YAML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// clearStyle converts the flow style of JSON into the block style.
+func clearStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, c := range node.Content {
+		clearStyle(c)
+	}
+}
+
+// escapeTemplate escapes the script so that it isn't rendered as a template by cmdx.
+func escapeTemplate(s string) string {
+	return strings.ReplaceAll(s, "{{", `{{"{{"}}`)
+}
+
+// pairs returns key and value nodes of the mapping node in order.
+func pairs(node *yaml.Node) [][2]*yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	arr := make([][2]*yaml.Node, 0, len(node.Content)/2) //nolint:mnd
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		arr = append(arr, [2]*yaml.Node{node.Content[i], node.Content[i+1]})
+	}
+	return arr
+}
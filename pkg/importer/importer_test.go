@@ -0,0 +1,37 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+	b, err := Marshal(&domain.Config{
+		Version: domain.ConfigVersion,
+		Vars: map[string]domain.Var{
+			"SHA": {Sh: "git rev-parse HEAD"},
+		},
+		Tasks: []domain.Task{
+			{
+				Name:      "build",
+				Script:    "go build",
+				DependsOn: []string{"gen"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `version: 1
+tasks:
+  - name: build
+    script: go build
+    depends_on:
+      - gen
+vars:
+  SHA:
+    sh: git rev-parse HEAD
+`, string(b))
+}
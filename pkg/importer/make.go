@@ -0,0 +1,463 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+var (
+	makeAssignPattern     = regexp.MustCompile(`^(?:(export|override)\s+)?([A-Za-z0-9_.-]+)\s*(\?=|::=|:=|\+=|!=|=)\s*(.*)$`)
+	makeRulePattern       = regexp.MustCompile(`^([^:#=]+?)\s*(::?)(.*)$`)
+	makeSpecialPattern    = regexp.MustCompile(`^\.[A-Z_]+$`)
+	makeIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	makeRefPattern        = regexp.MustCompile(`\$\([A-Za-z_][A-Za-z0-9_]*\)|\$\{[A-Za-z_][A-Za-z0-9_]*\}`)
+)
+
+// makeRule is a rule of Makefile.
+// A rule may have multiple targets.
+type makeRule struct {
+	targets []string
+	prereqs []string
+	recipe  []string
+	usage   string
+}
+
+// makeVar is a variable of Makefile.
+// If unsupported is true, the value refers other variables or functions, so it can't be converted.
+type makeVar struct {
+	name        string
+	value       string
+	overridable bool
+	export      bool
+	unsupported bool
+}
+
+type makefile struct {
+	rules    []*makeRule
+	vars     map[string]*makeVar
+	varNames []string
+	phony    map[string]struct{}
+}
+
+// importMakefile converts rules of Makefile into tasks.
+//
+//   - A target is converted into a task and prerequisites which are targets are converted into depends_on
+//   - If the target isn't phony, prerequisites which aren't targets are converted into sources and the target is converted into generates
+//   - Variables which are referred by the recipe are converted into flags, which are passed to the script as environment variables
+//   - Exported variables are converted into environment
+//   - The comment "## description" after the target or the comment line before the target is converted into usage
+func importMakefile(b []byte) (*Result, error) {
+	result := &Result{
+		Config: &domain.Config{},
+	}
+	mk, err := parseMakefile(b, result)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range mk.varNames {
+		v := mk.vars[name]
+		if !v.export {
+			continue
+		}
+		if v.unsupported {
+			result.warn("the exported variable %s refers other variables or functions, so it isn't converted into environment", name)
+			continue
+		}
+		if result.Config.Environment == nil {
+			result.Config.Environment = map[string]string{}
+		}
+		result.Config.Environment[name] = v.value
+	}
+	targets := map[string]struct{}{}
+	for _, rule := range mk.rules {
+		for _, t := range rule.targets {
+			targets[t] = struct{}{}
+		}
+	}
+	tasks := []domain.Task{}
+	indexes := map[string]int{}
+	for _, rule := range mk.rules {
+		for _, target := range rule.targets {
+			task := mk.task(target, rule, targets, result)
+			// A target can be defined in multiple rules.
+			// Prerequisites are merged and the last recipe is used.
+			if i, ok := indexes[target]; ok {
+				tasks[i] = mergeMakeTask(tasks[i], task)
+				continue
+			}
+			indexes[target] = len(tasks)
+			tasks = append(tasks, task)
+		}
+	}
+	result.Config.Tasks = tasks
+	return result, nil
+}
+
+func mergeMakeTask(a, b domain.Task) domain.Task {
+	for _, dep := range b.DependsOn {
+		if !slices.Contains(a.DependsOn, dep) {
+			a.DependsOn = append(a.DependsOn, dep)
+		}
+	}
+	a.Sources = append(a.Sources, b.Sources...)
+	if len(a.Sources) != 0 {
+		a.Generates = []string{a.Name}
+	}
+	if b.Script != "" {
+		a.Script = b.Script
+		a.Flags = b.Flags
+	}
+	if b.Usage != "" {
+		a.Usage = b.Usage
+	}
+	return a
+}
+
+func parseMakefile(b []byte, result *Result) (*makefile, error) {
+	mk := &makefile{
+		vars:  map[string]*makeVar{},
+		phony: map[string]struct{}{},
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	var (
+		rule    *makeRule
+		comment string
+		define  bool
+		lineNum int
+		// continued is true if the previous recipe line ends with a backslash.
+		continued bool
+	)
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if define {
+			if strings.TrimSpace(line) == "endef" {
+				define = false
+			}
+			continue
+		}
+		if rule != nil && (strings.HasPrefix(line, "\t") || continued) {
+			cmd := strings.TrimPrefix(line, "\t")
+			if continued {
+				rule.recipe[len(rule.recipe)-1] += "\n" + cmd
+			} else {
+				rule.recipe = append(rule.recipe, cmd)
+			}
+			continued = strings.HasSuffix(cmd, `\`)
+			continue
+		}
+		// Join lines which end with a backslash.
+		for strings.HasSuffix(line, `\`) && scanner.Scan() {
+			lineNum++
+			line = strings.TrimSuffix(line, `\`) + " " + strings.TrimSpace(scanner.Text())
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			comment = ""
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			comment = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			continue
+		}
+		usage := comment
+		comment = ""
+		directive, _, _ := strings.Cut(trimmed, " ")
+		switch directive {
+		case "define":
+			define = true
+			result.warn("line %d: define isn't supported", lineNum)
+			continue
+		case "ifeq", "ifneq", "ifdef", "ifndef":
+			result.warn("line %d: the conditional %s isn't supported. All lines in the conditional are converted", lineNum, directive)
+			continue
+		case "else", "endif":
+			continue
+		case "include", "-include", "sinclude", "vpath", "unexport":
+			result.warn("line %d: %s isn't supported", lineNum, directive)
+			continue
+		case "export":
+			if !strings.ContainsAny(trimmed, "=") {
+				result.warn("line %d: export without an assignment isn't supported", lineNum)
+				continue
+			}
+		}
+		if m := makeAssignPattern.FindStringSubmatch(trimmed); m != nil {
+			rule = nil
+			mk.assign(m, lineNum, result)
+			continue
+		}
+		m := makeRulePattern.FindStringSubmatch(trimmed)
+		if m == nil {
+			result.warn("line %d: the line can't be parsed: %s", lineNum, trimmed)
+			rule = nil
+			continue
+		}
+		rule = mk.rule(m, usage, lineNum, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mk, nil
+}
+
+func (mk *makefile) assign(m []string, lineNum int, result *Result) {
+	name, op, value := m[2], m[3], m[4]
+	if makeSpecialPattern.MatchString(name) {
+		return
+	}
+	if !makeIdentifierPattern.MatchString(name) {
+		result.warn("line %d: the variable name %s isn't supported", lineNum, name)
+		return
+	}
+	v, ok := mk.vars[name]
+	if !ok {
+		v = &makeVar{name: name}
+		mk.vars[name] = v
+		mk.varNames = append(mk.varNames, name)
+	}
+	v.export = v.export || m[1] == "export"
+	switch op {
+	case "+=":
+		if v.value != "" {
+			value = v.value + " " + value
+		}
+	case "?=":
+		if ok {
+			return
+		}
+		v.overridable = true
+	}
+	v.value = value
+	v.unsupported = op == "!=" || strings.Contains(value, "$")
+}
+
+func (mk *makefile) rule(m []string, usage string, lineNum int, result *Result) *makeRule {
+	rest := m[3]
+	if before, desc, ok := strings.Cut(rest, "##"); ok {
+		rest = before
+		usage = strings.TrimSpace(desc)
+	} else if before, _, ok := strings.Cut(rest, "#"); ok {
+		rest = before
+	}
+	var inline string
+	if before, cmd, ok := strings.Cut(rest, ";"); ok {
+		rest = before
+		inline = strings.TrimSpace(cmd)
+	}
+	targets := strings.Fields(m[1])
+	if slices.Contains(targets, ".PHONY") {
+		for _, t := range strings.Fields(rest) {
+			mk.phony[t] = struct{}{}
+		}
+		return nil
+	}
+	if makeAssignPattern.MatchString(strings.TrimSpace(rest)) {
+		result.warn("line %d: the target-specific variable isn't supported", lineNum)
+		return nil
+	}
+	rule := &makeRule{
+		usage: usage,
+	}
+	for _, prereq := range strings.Fields(rest) {
+		rule.prereqs = append(rule.prereqs, mk.expand(prereq))
+	}
+	for _, t := range targets {
+		t = mk.expand(t)
+		switch {
+		case makeSpecialPattern.MatchString(t):
+		case strings.ContainsAny(t, "%$"):
+			result.warn("line %d: the target %s isn't supported because pattern rules and variables in target names can't be converted", lineNum, t)
+		default:
+			rule.targets = append(rule.targets, t)
+		}
+	}
+	if inline != "" {
+		rule.recipe = append(rule.recipe, inline)
+	}
+	mk.rules = append(mk.rules, rule)
+	return rule
+}
+
+// expand expands variables in the target or prerequisite name.
+// Only variables whose values don't refer other variables or functions are expanded.
+func (mk *makefile) expand(s string) string {
+	return makeRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		v, ok := mk.vars[ref[2:len(ref)-1]]
+		if !ok || v.unsupported {
+			return ref
+		}
+		return v.value
+	})
+}
+
+func (mk *makefile) task(target string, rule *makeRule, targets map[string]struct{}, result *Result) domain.Task {
+	task := domain.Task{
+		Name:  target,
+		Usage: rule.usage,
+	}
+	_, phony := mk.phony[target]
+	files := []string{}
+	for _, prereq := range rule.prereqs {
+		if _, ok := targets[prereq]; ok {
+			task.DependsOn = append(task.DependsOn, prereq)
+			continue
+		}
+		if strings.ContainsAny(prereq, "%$") {
+			result.warn("target %s: the prerequisite %s isn't supported", target, prereq)
+			continue
+		}
+		files = append(files, prereq)
+	}
+	if len(files) != 0 {
+		if phony {
+			result.warn("target %s: prerequisites which aren't targets are ignored: %s", target, strings.Join(files, ", "))
+		} else {
+			task.Sources = files
+			task.Generates = []string{target}
+		}
+	}
+	lines := make([]string, 0, len(rule.recipe))
+	used := []string{}
+	for _, cmd := range rule.recipe {
+		line, vars := mk.translate(target, rule.prereqs, cmd, result)
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+		for _, v := range vars {
+			if !slices.Contains(used, v) {
+				used = append(used, v)
+			}
+		}
+	}
+	task.Script = strings.Join(lines, "\n")
+	for _, name := range used {
+		v := mk.vars[name]
+		flag := domain.Flag{
+			Name:       strings.ReplaceAll(strings.ToLower(name), "_", "-"),
+			ScriptEnvs: []string{envName(name)},
+		}
+		if v.overridable {
+			flag.InputEnvs = []string{envName(name)}
+		}
+		if v.unsupported {
+			result.warn("target %s: the variable %s refers other variables or functions, so the default value isn't converted", target, name)
+		} else {
+			flag.Default = v.value
+		}
+		task.Flags = append(task.Flags, flag)
+	}
+	return task
+}
+
+// translate converts the recipe line into the shell script.
+// It returns names of variables referred by the line.
+func (mk *makefile) translate(target string, prereqs []string, cmd string, result *Result) (string, []string) {
+	ignoreError := false
+	for {
+		trimmed := strings.TrimLeft(cmd, "@+- \t")
+		if len(trimmed) == len(cmd) {
+			break
+		}
+		ignoreError = ignoreError || strings.Contains(cmd[:len(cmd)-len(trimmed)], "-")
+		cmd = trimmed
+	}
+	if cmd == "" {
+		return "", nil
+	}
+	buf := &strings.Builder{}
+	vars := []string{}
+	for i := 0; i < len(cmd); i++ {
+		c := cmd[i]
+		if c != '$' || i+1 == len(cmd) {
+			buf.WriteByte(c)
+			continue
+		}
+		i++
+		switch next := cmd[i]; next {
+		case '$':
+			buf.WriteByte('$')
+		case '@':
+			buf.WriteString(target)
+		case '<':
+			if len(prereqs) != 0 {
+				buf.WriteString(prereqs[0])
+			}
+		case '^', '+':
+			buf.WriteString(strings.Join(prereqs, " "))
+		case '(', '{':
+			end := closing(cmd, i)
+			if end < 0 {
+				result.warn("target %s: the reference isn't closed: %s", target, cmd[i-1:])
+				buf.WriteString(cmd[i-1:])
+				i = len(cmd)
+				continue
+			}
+			name := cmd[i+1 : end]
+			switch {
+			case !makeIdentifierPattern.MatchString(name):
+				result.warn("target %s: functions and substitution references aren't supported: %s", target, cmd[i-1:end+1])
+				buf.WriteString(cmd[i-1 : end+1])
+			case mk.vars[name] != nil:
+				buf.WriteString("${" + envName(name) + "}")
+				vars = append(vars, name)
+			default:
+				// Undefined variables are empty in Makefile.
+				buf.WriteString("${" + name + ":-}")
+			}
+			i = end
+		default:
+			if makeIdentifierPattern.MatchString(string(next)) {
+				if mk.vars[string(next)] != nil {
+					vars = append(vars, string(next))
+					buf.WriteString("${" + envName(string(next)) + "}")
+				} else {
+					buf.WriteString("${" + string(next) + ":-}")
+				}
+				continue
+			}
+			result.warn("target %s: the automatic variable $%c isn't supported", target, next)
+			buf.WriteByte('$')
+			buf.WriteByte(next)
+		}
+	}
+	line := escapeTemplate(buf.String())
+	if ignoreError {
+		line += " || true"
+	}
+	return line, vars
+}
+
+// envName returns the name of the environment variable which cmdx sets for script_envs and input_envs.
+// cmdx converts the name into upper case and replaces - with _.
+func envName(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// closing returns the index of the parenthesis or the brace which closes the one at the index start.
+func closing(s string, start int) int {
+	open := s[start]
+	var end byte = ')'
+	if open == '{' {
+		end = '}'
+	}
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case open:
+			depth++
+		case end:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+func Test_importMakefile(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title    string
+		makefile string
+		exp      *domain.Config
+		warnings int
+	}{
+		{
+			title: "phony targets and usage",
+			makefile: `.PHONY: test lint
+# run tests
+test: lint ## run all tests
+	go test ./...
+lint:
+	@golangci-lint run
+`,
+			exp: &domain.Config{
+				Tasks: []domain.Task{
+					{
+						Name:      "test",
+						Usage:     "run all tests",
+						Script:    "go test ./...",
+						DependsOn: []string{"lint"},
+					},
+					{
+						Name:   "lint",
+						Script: "golangci-lint run",
+					},
+				},
+			},
+		},
+		{
+			title: "variables, automatic variables, and file targets",
+			makefile: `GO ?= go
+BIN := bin/app
+export CGO_ENABLED = 0
+
+$(BIN): main.go go.mod
+	$(GO) build -o $@ $<
+	-rm -f $$TMPDIR/cache
+`,
+			exp: &domain.Config{
+				Environment: map[string]string{"CGO_ENABLED": "0"},
+				Tasks: []domain.Task{
+					{
+						Name: "bin/app",
+						Flags: []domain.Flag{
+							{
+								Name:       "go",
+								Default:    "go",
+								InputEnvs:  []string{"GO"},
+								ScriptEnvs: []string{"GO"},
+							},
+						},
+						Script:    "${GO} build -o bin/app main.go\nrm -f $TMPDIR/cache || true",
+						Sources:   []string{"main.go", "go.mod"},
+						Generates: []string{"bin/app"},
+					},
+				},
+			},
+		},
+		{
+			title: "lowercase variables",
+			makefile: `prefix = /usr/local
+install:
+	echo $(prefix)
+`,
+			exp: &domain.Config{
+				Tasks: []domain.Task{
+					{
+						Name: "install",
+						Flags: []domain.Flag{
+							{
+								Name:       "prefix",
+								Default:    "/usr/local",
+								ScriptEnvs: []string{"PREFIX"},
+							},
+						},
+						// cmdx exports the flag as the upper case environment variable.
+						Script: "echo ${PREFIX}",
+					},
+				},
+			},
+		},
+		{
+			title: "functions and pattern rules",
+			makefile: `%.o: %.c
+	cc -c $<
+files:
+	echo $(wildcard *.go)
+`,
+			exp: &domain.Config{
+				Tasks: []domain.Task{
+					{
+						Name:   "files",
+						Script: "echo $(wildcard *.go)",
+					},
+				},
+			},
+			warnings: 2,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			result, err := importMakefile([]byte(d.makefile))
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, result.Config)
+			assert.Len(t, result.Warnings, d.warnings)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package importer
+
+import (
+	"strings"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"go.yaml.in/yaml/v3"
+)
+
+// importNPM converts scripts of package.json into tasks.
+// Scripts "pre<name>" and "post<name>" are converted into `before` and `after` of the task "<name>".
+func importNPM(b []byte) (*Result, error) {
+	result := &Result{
+		Config: &domain.Config{},
+	}
+	// package.json is parsed as YAML to keep the order of scripts.
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(b, doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return result, nil
+	}
+	scripts := map[string]string{}
+	names := []string{}
+	for _, p := range pairs(doc.Content[0]) {
+		if p[0].Value != "scripts" {
+			continue
+		}
+		for _, s := range pairs(p[1]) {
+			if s[1].Kind != yaml.ScalarNode {
+				result.warn("the script %s isn't a string", s[0].Value)
+				continue
+			}
+			scripts[s[0].Value] = s[1].Value
+			names = append(names, s[0].Value)
+		}
+	}
+	if len(names) == 0 {
+		result.warn("package.json has no script")
+		return result, nil
+	}
+	for _, name := range names {
+		if hook(name, scripts) {
+			continue
+		}
+		task := domain.Task{
+			Name:   name,
+			Script: escapeTemplate(scripts[name]),
+		}
+		if s, ok := scripts["pre"+name]; ok {
+			task.Before = escapeTemplate(s)
+		}
+		if s, ok := scripts["post"+name]; ok {
+			task.After = escapeTemplate(s)
+		}
+		result.Config.Tasks = append(result.Config.Tasks, task)
+	}
+	result.warn("npm adds node_modules/.bin to PATH, but cmdx doesn't. Commands installed by npm may need to be run by npx")
+	return result, nil
+}
+
+// hook returns true if the script is a pre or post hook of another script.
+func hook(name string, scripts map[string]string) bool {
+	for _, prefix := range []string{"pre", "post"} {
+		if s, ok := strings.CutPrefix(name, prefix); ok {
+			if _, ok := scripts[s]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
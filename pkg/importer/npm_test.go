@@ -0,0 +1,45 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+func Test_importNPM(t *testing.T) {
+	t.Parallel()
+	result, err := importNPM([]byte(`{
+  "name": "foo",
+  "scripts": {
+    "test": "jest",
+    "prebuild": "rm -rf dist",
+    "build": "tsc",
+    "postbuild": "echo {{done}}",
+    "preview": "vite preview"
+  }
+}`))
+	require.NoError(t, err)
+	assert.Equal(t, &domain.Config{
+		Tasks: []domain.Task{
+			{
+				Name:   "test",
+				Script: "jest",
+			},
+			{
+				Name:   "build",
+				Script: "tsc",
+				Hooks: domain.Hooks{
+					Before: "rm -rf dist",
+					After:  `echo {{"{{"}}done}}`,
+				},
+			},
+			{
+				Name:   "preview",
+				Script: "vite preview",
+			},
+		},
+	}, result.Config)
+	assert.Len(t, result.Warnings, 1)
+}
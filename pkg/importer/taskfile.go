@@ -0,0 +1,433 @@
+package importer
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/variable"
+	"go.yaml.in/yaml/v3"
+)
+
+// taskfileSpecialVars are variables which go-task sets.
+var taskfileSpecialVars = map[string]struct{}{ //nolint:gochecknoglobals
+	"CLI_ARGS":          {},
+	"CLI_FORCE":         {},
+	"CLI_SILENT":        {},
+	"CLI_VERBOSE":       {},
+	"TASK":              {},
+	"ALIAS":             {},
+	"TASK_EXE":          {},
+	"ROOT_TASKFILE":     {},
+	"ROOT_DIR":          {},
+	"TASKFILE":          {},
+	"TASKFILE_DIR":      {},
+	"TASK_DIR":          {},
+	"USER_WORKING_DIR":  {},
+	"CHECKSUM":          {},
+	"TIMESTAMP":         {},
+	"TASK_VERSION":      {},
+	"ITEM":              {},
+	"EXIT_CODE":         {},
+	"MATCH":             {},
+	"CLI_ASSUME_YES":    {},
+	"CLI_OFFLINE":       {},
+	"TASKFILE_VERSION":  {},
+	"TASK_ARGS":         {},
+	"TASK_REMOTE_DIR":   {},
+	"TASK_TEMP_DIR":     {},
+	"CLI_ARGS_LIST":     {},
+	"TASK_NAME":         {},
+	"ROOT_TASKFILE_DIR": {},
+}
+
+// taskfileVar is a variable of Taskfile.
+// Static variables are converted into flags and other variables are converted into vars.
+type taskfileVar struct {
+	name   string
+	value  string
+	static bool
+	v      domain.Var
+}
+
+type taskfileConverter struct {
+	result *Result
+	vars   []taskfileVar
+}
+
+// importTaskfile converts tasks of Taskfile.yml of go-task into tasks.
+//
+//   - desc and summary are converted into usage and description
+//   - deps are converted into depends_on
+//   - cmds are converted into script. If cmds call other tasks or ignore errors, cmds are converted into steps
+//   - defer commands are converted into finally
+//   - Static variables which are referred by the task are converted into flags. Variables with templates or sh are converted into vars
+//   - Variables which are referred by the task but aren't defined are converted into flags without default values, so that other tasks can pass them
+//   - env, dotenv, sources, generates, status, and silent are converted into the same settings of cmdx
+func importTaskfile(b []byte) (*Result, error) {
+	c := &taskfileConverter{
+		result: &Result{
+			Config: &domain.Config{},
+		},
+	}
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(b, doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return c.result, nil
+	}
+	cfg := c.result.Config
+	var tasks *yaml.Node
+	for _, p := range pairs(doc.Content[0]) {
+		key, value := p[0].Value, p[1]
+		switch key {
+		case "version":
+			if !strings.HasPrefix(value.Value, "3") {
+				c.result.warn("only the version 3 of Taskfile is supported: %s", value.Value)
+			}
+		case "vars":
+			c.vars = c.parseVars(value, "")
+			for _, v := range c.vars {
+				if !v.static {
+					if cfg.Vars == nil {
+						cfg.Vars = map[string]domain.Var{}
+					}
+					cfg.Vars[v.name] = v.v
+				}
+			}
+		case "env":
+			cfg.Environment = c.env(value, "")
+		case "dotenv":
+			cfg.EnvFiles = c.envFiles(value)
+		case "silent":
+			quiet := value.Value == "true"
+			cfg.Quiet = &quiet
+		case "tasks":
+			tasks = value
+		default:
+			c.result.warn("%s isn't supported", key)
+		}
+	}
+	for _, p := range pairs(tasks) {
+		cfg.Tasks = append(cfg.Tasks, c.task(p[0].Value, p[1]))
+	}
+	c.fixStepFlags()
+	return c.result, nil
+}
+
+// parseVars parses vars.
+// taskName is empty if vars are global.
+func (c *taskfileConverter) parseVars(node *yaml.Node, taskName string) []taskfileVar {
+	vars := []taskfileVar{}
+	for _, p := range pairs(node) {
+		name, value := p[0].Value, p[1]
+		switch value.Kind { //nolint:exhaustive
+		case yaml.ScalarNode:
+			if strings.Contains(value.Value, "{{") {
+				vars = append(vars, taskfileVar{name: name, v: domain.Var{Value: value.Value}})
+				continue
+			}
+			vars = append(vars, taskfileVar{name: name, value: value.Value, static: true})
+		case yaml.MappingNode:
+			if sh := mappingScalar(value, "sh"); sh != "" {
+				vars = append(vars, taskfileVar{name: name, v: domain.Var{Sh: sh}})
+				continue
+			}
+			c.warnTask(taskName, "the variable %s isn't supported. Only strings and sh are supported", name)
+		default:
+			c.warnTask(taskName, "the variable %s isn't supported. Only strings and sh are supported", name)
+		}
+	}
+	return vars
+}
+
+func (c *taskfileConverter) env(node *yaml.Node, taskName string) map[string]string {
+	env := map[string]string{}
+	for _, p := range pairs(node) {
+		if p[1].Kind != yaml.ScalarNode {
+			c.warnTask(taskName, "the environment variable %s isn't supported. Only strings are supported", p[0].Value)
+			continue
+		}
+		env[p[0].Value] = p[1].Value
+	}
+	if len(env) == 0 {
+		return nil
+	}
+	return env
+}
+
+func (c *taskfileConverter) envFiles(node *yaml.Node) []domain.EnvFile {
+	files := []domain.EnvFile{}
+	for _, s := range stringList(node) {
+		// go-task skips dotenv files which don't exist.
+		files = append(files, domain.EnvFile{Path: s, Optional: true})
+	}
+	return files
+}
+
+func (c *taskfileConverter) warnTask(taskName, format string, a ...any) {
+	if taskName == "" {
+		c.result.warn(format, a...)
+		return
+	}
+	c.result.warn("task "+taskName+": "+format, a...)
+}
+
+func (c *taskfileConverter) task(name string, node *yaml.Node) domain.Task {
+	task := domain.Task{
+		Name: name,
+	}
+	var cmds *yaml.Node
+	localVars := []taskfileVar{}
+	switch node.Kind { //nolint:exhaustive
+	case yaml.ScalarNode, yaml.SequenceNode:
+		// The short syntax such as `build: go build` and `build: [go build]`
+		cmds = node
+	case yaml.MappingNode:
+		for _, p := range pairs(node) {
+			key, value := p[0].Value, p[1]
+			switch key {
+			case "desc":
+				task.Usage = value.Value
+			case "summary":
+				task.Description = strings.TrimSpace(value.Value)
+			case "cmds", "cmd":
+				cmds = value
+			case "deps":
+				task.DependsOn = c.deps(name, value)
+			case "vars":
+				localVars = c.parseVars(value, name)
+			case "env":
+				task.Environment = c.env(value, name)
+			case "dotenv":
+				task.EnvFiles = c.envFiles(value)
+			case "sources":
+				task.Sources = c.globs(name, value)
+			case "generates":
+				task.Generates = c.globs(name, value)
+			case "status":
+				task.Status = stringList(value)
+			case "silent":
+				quiet := value.Value == "true"
+				task.Quiet = &quiet
+			case "dir":
+				c.warnTask(name, "dir isn't supported. The task is run in the directory where the configuration file exists")
+			default:
+				c.warnTask(name, "%s isn't supported", key)
+			}
+		}
+	}
+	if cmds != nil {
+		c.cmds(&task, cmds)
+	}
+	if len(task.Generates) != 0 && len(task.Sources) == 0 {
+		c.warnTask(name, "generates is ignored because sources isn't set")
+		task.Generates = nil
+	}
+	c.setVars(&task, localVars)
+	return task
+}
+
+func (c *taskfileConverter) globs(taskName string, node *yaml.Node) []string {
+	globs := []string{}
+	for _, s := range stringList(node) {
+		if strings.HasPrefix(s, "!") {
+			c.warnTask(taskName, "the exclusion %s isn't supported", s)
+			continue
+		}
+		globs = append(globs, s)
+	}
+	return globs
+}
+
+func (c *taskfileConverter) deps(taskName string, node *yaml.Node) []string {
+	deps := []string{}
+	for _, dep := range node.Content {
+		if dep.Kind == yaml.ScalarNode {
+			deps = append(deps, dep.Value)
+			continue
+		}
+		t := mappingScalar(dep, "task")
+		if t == "" {
+			c.warnTask(taskName, "the dependency isn't supported")
+			continue
+		}
+		if mappingValue(dep, "vars") != nil {
+			c.warnTask(taskName, "vars of the dependency %s aren't supported", t)
+		}
+		deps = append(deps, t)
+	}
+	return deps
+}
+
+// cmds converts cmds into script.
+// If cmds call other tasks or ignore errors, cmds are converted into steps.
+func (c *taskfileConverter) cmds(task *domain.Task, node *yaml.Node) {
+	items := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		items = node.Content
+	}
+	steps := []domain.Step{}
+	useSteps := false
+	finally := []string{}
+	for _, item := range items {
+		if item.Kind == yaml.ScalarNode {
+			steps = append(steps, domain.Step{Script: item.Value})
+			continue
+		}
+		if d := mappingValue(item, "defer"); d != nil {
+			if d.Kind != yaml.ScalarNode {
+				c.warnTask(task.Name, "defer which calls a task isn't supported")
+				continue
+			}
+			finally = append(finally, d.Value)
+			continue
+		}
+		step := domain.Step{}
+		for _, p := range pairs(item) {
+			key, value := p[0].Value, p[1]
+			switch key {
+			case "cmd":
+				step.Script = value.Value
+			case "task":
+				step.Task = value.Value
+				useSteps = true
+			case "vars":
+				step.Flags = c.env(value, task.Name)
+			case "ignore_error":
+				step.IgnoreError = value.Value == "true"
+				useSteps = true
+			case "silent":
+			default:
+				c.warnTask(task.Name, "%s of cmds isn't supported", key)
+			}
+		}
+		if step.Script == "" && step.Task == "" {
+			continue
+		}
+		steps = append(steps, step)
+	}
+	if len(finally) != 0 {
+		// go-task runs deferred commands in the reverse order.
+		slices.Reverse(finally)
+		task.Finally = strings.Join(finally, "\n")
+	}
+	if useSteps {
+		task.Steps = steps
+		return
+	}
+	lines := make([]string, len(steps))
+	for i, step := range steps {
+		lines[i] = step.Script
+	}
+	task.Script = strings.Join(lines, "\n")
+}
+
+// setVars converts variables referred by the task into flags and vars.
+// Task level variables override global variables.
+func (c *taskfileConverter) setVars(task *domain.Task, localVars []taskfileVar) {
+	templates := []string{task.Script, task.Finally}
+	for _, step := range task.Steps {
+		templates = append(templates, step.Script)
+	}
+	for _, v := range localVars {
+		templates = append(templates, v.v.Value)
+	}
+	refs := variable.References(templates...)
+	for _, name := range refs {
+		if _, ok := taskfileSpecialVars[name]; ok {
+			c.warnTask(task.Name, "the special variable %s isn't supported", name)
+		}
+	}
+	for _, v := range localVars {
+		if !v.static {
+			if task.Vars == nil {
+				task.Vars = map[string]domain.Var{}
+			}
+			task.Vars[v.name] = v.v
+		}
+	}
+	seen := map[string]struct{}{}
+	for _, vars := range [][]taskfileVar{localVars, c.vars} {
+		for _, v := range vars {
+			if _, ok := seen[v.name]; ok {
+				continue
+			}
+			seen[v.name] = struct{}{}
+			if !v.static || !slices.Contains(refs, v.name) {
+				continue
+			}
+			task.Flags = append(task.Flags, domain.Flag{
+				Name:    v.name,
+				Default: v.value,
+			})
+		}
+	}
+	// Variables which aren't defined may be passed by other tasks such as `task: bye` with `vars: {WHO: you}`.
+	for _, name := range refs {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		if _, ok := taskfileSpecialVars[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		task.Flags = append(task.Flags, domain.Flag{
+			Name: name,
+		})
+	}
+}
+
+// fixStepFlags removes flags of steps which aren't defined in the called task.
+func (c *taskfileConverter) fixStepFlags() {
+	tasks := c.result.Config.Tasks
+	flags := map[string][]domain.Flag{}
+	for _, task := range tasks {
+		flags[task.Name] = task.Flags
+	}
+	for i, task := range tasks {
+		for j, step := range task.Steps {
+			for name := range step.Flags {
+				if !slices.ContainsFunc(flags[step.Task], func(flag domain.Flag) bool {
+					return flag.Name == name
+				}) {
+					c.warnTask(task.Name, "the variable %s passed to the task %s isn't a flag of the task, so it is ignored", name, step.Task)
+					delete(tasks[i].Steps[j].Flags, name)
+				}
+			}
+		}
+	}
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for _, p := range pairs(node) {
+		if p[0].Value == key {
+			return p[1]
+		}
+	}
+	return nil
+}
+
+func mappingScalar(node *yaml.Node, key string) string {
+	v := mappingValue(node, key)
+	if v == nil || v.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return v.Value
+}
+
+// stringList returns the list of strings.
+// A string is treated as a list with one element.
+func stringList(node *yaml.Node) []string {
+	if node.Kind == yaml.ScalarNode {
+		return []string{node.Value}
+	}
+	arr := make([]string, 0, len(node.Content))
+	for _, c := range node.Content {
+		if c.Kind == yaml.ScalarNode {
+			arr = append(arr, c.Value)
+		}
+	}
+	return arr
+}
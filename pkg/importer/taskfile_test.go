@@ -0,0 +1,155 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+func Test_importTaskfile(t *testing.T) {
+	t.Parallel()
+	quiet := true
+	data := []struct {
+		title    string
+		taskfile string
+		exp      *domain.Config
+		warnings []string
+	}{
+		{
+			title: "short syntax and variables",
+			taskfile: `version: '3'
+vars:
+  NAME: world
+  SHA:
+    sh: git rev-parse HEAD
+env:
+  FOO: foo
+tasks:
+  hello: echo hello {{.NAME}}
+  build:
+    desc: build the app
+    summary: |
+      build the app with the commit hash
+    deps: [gen]
+    silent: true
+    sources: ['**/*.go']
+    generates: [bin/app]
+    cmds:
+      - go build -ldflags "-X main.sha={{.SHA}}" -o bin/app .
+      - defer: rm -f a
+      - defer: rm -f b
+  gen:
+    - go generate ./...
+`,
+			exp: &domain.Config{
+				Environment: map[string]string{"FOO": "foo"},
+				Vars: map[string]domain.Var{
+					"SHA": {Sh: "git rev-parse HEAD"},
+				},
+				Tasks: []domain.Task{
+					{
+						Name:   "hello",
+						Flags:  []domain.Flag{{Name: "NAME", Default: "world"}},
+						Script: "echo hello {{.NAME}}",
+					},
+					{
+						Name:        "build",
+						Usage:       "build the app",
+						Description: "build the app with the commit hash",
+						Script:      `go build -ldflags "-X main.sha={{.SHA}}" -o bin/app .`,
+						Hooks:       domain.Hooks{Finally: "rm -f b\nrm -f a"},
+						DependsOn:   []string{"gen"},
+						Sources:     []string{"**/*.go"},
+						Generates:   []string{"bin/app"},
+						Quiet:       &quiet,
+					},
+					{
+						Name:   "gen",
+						Script: "go generate ./...",
+					},
+				},
+			},
+		},
+		{
+			title: "steps",
+			taskfile: `version: '3'
+tasks:
+  greet:
+    vars:
+      NAME: world
+    cmds:
+      - echo {{.NAME}}
+  release:
+    aliases: [r]
+    cmds:
+      - task: greet
+        vars:
+          NAME: cmdx
+          AGE: 10
+      - cmd: git tag {{.CLI_ARGS}}
+        ignore_error: true
+`,
+			exp: &domain.Config{
+				Tasks: []domain.Task{
+					{
+						Name:   "greet",
+						Flags:  []domain.Flag{{Name: "NAME", Default: "world"}},
+						Script: "echo {{.NAME}}",
+					},
+					{
+						Name: "release",
+						Steps: []domain.Step{
+							{Task: "greet", Flags: map[string]string{"NAME": "cmdx"}},
+							{Script: "git tag {{.CLI_ARGS}}", IgnoreError: true},
+						},
+					},
+				},
+			},
+			warnings: []string{
+				"task release: aliases isn't supported",
+				"task release: the special variable CLI_ARGS isn't supported",
+				"task release: the variable AGE passed to the task greet isn't a flag of the task, so it is ignored",
+			},
+		},
+		{
+			title: "variables passed to the called task",
+			taskfile: `version: '3'
+tasks:
+  bye:
+    cmds:
+      - echo bye {{.WHO}}
+  main:
+    cmds:
+      - task: bye
+        vars:
+          WHO: you
+`,
+			exp: &domain.Config{
+				Tasks: []domain.Task{
+					{
+						Name:   "bye",
+						Flags:  []domain.Flag{{Name: "WHO"}},
+						Script: "echo bye {{.WHO}}",
+					},
+					{
+						Name: "main",
+						Steps: []domain.Step{
+							{Task: "bye", Flags: map[string]string{"WHO": "you"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			result, err := importTaskfile([]byte(d.taskfile))
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, result.Config)
+			assert.Equal(t, d.warnings, result.Warnings)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package scaffold
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Candidate is a starter task proposed to the user.
+type Candidate struct {
+	Project string
+	File    string
+	Task    Task
+	// Default is true if the task is selected by default.
+	// If multiple projects propose the same task name, only the first one is selected by default.
+	Default bool
+}
+
+// Label returns the label of the candidate which is shown in the prompt.
+func (c Candidate) Label() string {
+	return c.Task.Name + ": " + c.Task.Script + " (" + c.Project + ")"
+}
+
+// Candidates returns the starter tasks of the projects.
+func Candidates(projects []Project) []Candidate {
+	names := map[string]struct{}{}
+	candidates := []Candidate{}
+	for _, project := range projects {
+		for _, task := range project.Tasks {
+			_, ok := names[task.Name]
+			names[task.Name] = struct{}{}
+			candidates = append(candidates, Candidate{
+				Project: project.Name,
+				File:    project.File,
+				Task:    task,
+				Default: !ok,
+			})
+		}
+	}
+	return candidates
+}
+
+// task is a task rendered in the configuration file.
+// Header is true if the comment of the project is rendered before the task.
+type task struct {
+	Candidate
+
+	Header bool
+}
+
+const configTemplate = `---
+# the configuration file of cmdx, which is a task runner.
+# https://github.com/suzuki-shunsuke/cmdx
+# This file was generated by 'cmdx --init'.
+# Detected projects:
+{{- range .Projects}}
+# - {{.Name}} ({{.File}})
+{{- end}}
+version: 1
+# timeout:
+#   duration: 600
+#   kill_after: 30
+# environment:
+#   FOO: foo
+tasks:
+{{- range $i, $t := .Tasks}}
+{{- if $t.Header}}
+# {{$t.Project}} ({{$t.File}})
+{{- end}}
+- name: {{quote $t.Task.Name}}
+  usage: {{quote $t.Task.Usage}}
+  {{- if eq $i 0}}
+  # flags:
+  # - name: verbose
+  #   short: v
+  #   type: bool
+  {{- end}}
+  {{- if $t.Task.Exec}}
+  require:
+    exec:
+    {{- range $t.Task.Exec}}
+    - {{quote .}}
+    {{- end}}
+  {{- end}}
+  script: {{quote $t.Task.Script}}
+{{- else}} []
+{{- end}}
+`
+
+// Render renders the configuration file with the selected tasks.
+// If task names duplicate, the project name is appended to the later task name such as "test-make".
+func Render(projects []Project, selected []Candidate) ([]byte, error) {
+	tpl, err := template.New("config").Funcs(template.FuncMap{
+		"quote": quote,
+	}).Parse(configTemplate)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]struct{}{}
+	tasks := make([]task, len(selected))
+	for i, c := range selected {
+		if _, ok := names[c.Task.Name]; ok {
+			c.Task.Name += "-" + c.Project
+		}
+		names[c.Task.Name] = struct{}{}
+		tasks[i] = task{
+			Candidate: c,
+			Header:    i == 0 || selected[i-1].Project != c.Project,
+		}
+	}
+	buf := &bytes.Buffer{}
+	if err := tpl.Execute(buf, map[string]any{
+		"Projects": projects,
+		"Tasks":    tasks,
+	}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// quote returns the YAML scalar of the string.
+// The string is quoted only if it is needed.
+func quote(s string) string {
+	b, err := yaml.Marshal(s)
+	if err != nil {
+		return `""`
+	}
+	return strings.TrimSuffix(string(b), "\n")
+}
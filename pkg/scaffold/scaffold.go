@@ -0,0 +1,178 @@
+// Package scaffold generates the configuration file for the project.
+// It detects the project type by files such as go.mod and package.json and proposes starter tasks.
+package scaffold
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Project is a detected project type and its starter tasks.
+type Project struct {
+	Name string
+	// File is the file by which the project is detected.
+	File  string
+	Tasks []Task
+}
+
+// Task is a starter task.
+type Task struct {
+	Name   string
+	Usage  string
+	Script string
+	// Exec is commands which are required by the task.
+	Exec []string
+}
+
+type detector func(dir string) *Project
+
+// Detect detects project types in the directory.
+// Projects are returned in the fixed order, go, node, rust, python, and make.
+func Detect(dir string) []Project {
+	detectors := []detector{
+		detectGo,
+		detectNode,
+		detectRust,
+		detectPython,
+		detectMake,
+	}
+	projects := []Project{}
+	for _, detect := range detectors {
+		if p := detect(dir); p != nil && len(p.Tasks) != 0 {
+			projects = append(projects, *p)
+		}
+	}
+	return projects
+}
+
+func exist(dir, name string) bool {
+	_, err := os.Stat(filepath.Join(dir, name))
+	return err == nil
+}
+
+func detectGo(dir string) *Project {
+	if !exist(dir, "go.mod") {
+		return nil
+	}
+	return &Project{
+		Name: "go",
+		File: "go.mod",
+		Tasks: []Task{
+			{Name: "test", Usage: "run tests", Script: "go test ./...", Exec: []string{"go"}},
+			{Name: "lint", Usage: "run linters", Script: "golangci-lint run", Exec: []string{"golangci-lint"}},
+			{Name: "build", Usage: "build the project", Script: "go build ./...", Exec: []string{"go"}},
+		},
+	}
+}
+
+// detectNode proposes tasks for scripts test, lint, and build in package.json.
+// The package manager is detected by the lock file.
+func detectNode(dir string) *Project {
+	b, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	pkg := struct {
+		Scripts map[string]string `json:"scripts"`
+	}{}
+	if err := json.Unmarshal(b, &pkg); err != nil {
+		return nil
+	}
+	pm := "npm"
+	switch {
+	case exist(dir, "pnpm-lock.yaml"):
+		pm = "pnpm"
+	case exist(dir, "yarn.lock"):
+		pm = "yarn"
+	}
+	project := &Project{
+		Name: "node",
+		File: "package.json",
+	}
+	for _, task := range []Task{
+		{Name: "test", Usage: "run tests"},
+		{Name: "lint", Usage: "run linters"},
+		{Name: "build", Usage: "build the project"},
+	} {
+		if _, ok := pkg.Scripts[task.Name]; !ok {
+			continue
+		}
+		task.Script = pm + " run " + task.Name
+		task.Exec = []string{pm}
+		project.Tasks = append(project.Tasks, task)
+	}
+	return project
+}
+
+func detectRust(dir string) *Project {
+	if !exist(dir, "Cargo.toml") {
+		return nil
+	}
+	return &Project{
+		Name: "rust",
+		File: "Cargo.toml",
+		Tasks: []Task{
+			{Name: "test", Usage: "run tests", Script: "cargo test", Exec: []string{"cargo"}},
+			{Name: "lint", Usage: "run linters", Script: "cargo clippy -- -D warnings", Exec: []string{"cargo"}},
+			{Name: "build", Usage: "build the project", Script: "cargo build", Exec: []string{"cargo"}},
+		},
+	}
+}
+
+// detectPython proposes tasks run by uv if uv.lock exists.
+func detectPython(dir string) *Project {
+	if !exist(dir, "pyproject.toml") {
+		return nil
+	}
+	project := &Project{
+		Name: "python",
+		File: "pyproject.toml",
+		Tasks: []Task{
+			{Name: "test", Usage: "run tests", Script: "pytest", Exec: []string{"pytest"}},
+			{Name: "lint", Usage: "run linters", Script: "ruff check .", Exec: []string{"ruff"}},
+			{Name: "build", Usage: "build the package", Script: "python -m build", Exec: []string{"python"}},
+		},
+	}
+	if exist(dir, "uv.lock") {
+		for i, task := range project.Tasks {
+			task.Script = "uv run " + task.Script
+			task.Exec = []string{"uv"}
+			project.Tasks[i] = task
+		}
+		project.Tasks[2].Script = "uv build"
+	}
+	return project
+}
+
+var makeTargetPattern = regexp.MustCompile(`(?m)^([A-Za-z0-9_.-]+)\s*:([^=]|$)`)
+
+// detectMake proposes tasks for targets test, lint, and build in Makefile.
+func detectMake(dir string) *Project {
+	b, err := os.ReadFile(filepath.Join(dir, "Makefile"))
+	if err != nil {
+		return nil
+	}
+	targets := map[string]struct{}{}
+	for _, m := range makeTargetPattern.FindAllSubmatch(b, -1) {
+		targets[string(m[1])] = struct{}{}
+	}
+	project := &Project{
+		Name: "make",
+		File: "Makefile",
+	}
+	for _, task := range []Task{
+		{Name: "test", Usage: "run tests"},
+		{Name: "lint", Usage: "run linters"},
+		{Name: "build", Usage: "build the project"},
+	} {
+		if _, ok := targets[task.Name]; !ok {
+			continue
+		}
+		task.Script = "make " + task.Name
+		task.Exec = []string{"make"}
+		project.Tasks = append(project.Tasks, task)
+	}
+	return project
+}
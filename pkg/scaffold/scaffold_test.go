@@ -0,0 +1,140 @@
+package scaffold
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/validate"
+	"go.yaml.in/yaml/v3"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		files map[string]string
+		exp   map[string][]string
+	}{
+		{
+			title: "no project",
+			exp:   map[string][]string{},
+		},
+		{
+			title: "go and make",
+			files: map[string]string{
+				"go.mod":   "module example.com/foo\n",
+				"Makefile": "X := 1\ntest: build\n\tgo test ./...\nbuild:\n\tgo build\n",
+			},
+			exp: map[string][]string{
+				"go":   {"go test ./...", "golangci-lint run", "go build ./..."},
+				"make": {"make test", "make build"},
+			},
+		},
+		{
+			title: "node scripts",
+			files: map[string]string{
+				"package.json":   `{"scripts": {"test": "jest", "build": "tsc", "start": "node ."}}`,
+				"pnpm-lock.yaml": "",
+			},
+			exp: map[string][]string{
+				"node": {"pnpm run test", "pnpm run build"},
+			},
+		},
+		{
+			title: "python with uv and rust",
+			files: map[string]string{
+				"pyproject.toml": "",
+				"uv.lock":        "",
+				"Cargo.toml":     "",
+			},
+			exp: map[string][]string{
+				"rust":   {"cargo test", "cargo clippy -- -D warnings", "cargo build"},
+				"python": {"uv run pytest", "uv run ruff check .", "uv build"},
+			},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			for name, content := range d.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+			}
+			scripts := map[string][]string{}
+			for _, p := range Detect(dir) {
+				for _, task := range p.Tasks {
+					scripts[p.Name] = append(scripts[p.Name], task.Script)
+				}
+			}
+			assert.Equal(t, d.exp, scripts)
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+	projects := []Project{
+		{
+			Name: "go",
+			File: "go.mod",
+			Tasks: []Task{
+				{Name: "test", Usage: "run tests", Script: "go test ./...", Exec: []string{"go"}},
+			},
+		},
+		{
+			Name: "make",
+			File: "Makefile",
+			Tasks: []Task{
+				{Name: "test", Usage: "run tests", Script: "make test", Exec: []string{"make"}},
+				{Name: "lint", Usage: "run linters", Script: "make lint: all", Exec: []string{"make"}},
+			},
+		},
+	}
+	data := []struct {
+		title    string
+		selected func([]Candidate) []Candidate
+		exp      []string
+	}{
+		{
+			title: "defaults",
+			selected: func(candidates []Candidate) []Candidate {
+				selected, err := Select(candidates, true)
+				require.NoError(t, err)
+				return selected
+			},
+			exp: []string{"test", "lint"},
+		},
+		{
+			title:    "duplicated names",
+			selected: func(candidates []Candidate) []Candidate { return candidates },
+			exp:      []string{"test", "test-make", "lint"},
+		},
+		{
+			title:    "no task",
+			selected: func([]Candidate) []Candidate { return nil },
+			exp:      []string{},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			b, err := Render(projects, d.selected(Candidates(projects)))
+			require.NoError(t, err)
+			cfg := &domain.Config{}
+			dec := yaml.NewDecoder(bytes.NewReader(b))
+			dec.KnownFields(true)
+			require.NoError(t, dec.Decode(cfg))
+			require.NoError(t, validate.Config(cfg))
+			names := []string{}
+			for _, task := range cfg.Tasks {
+				names = append(names, task.Name)
+			}
+			assert.Equal(t, d.exp, names)
+		})
+	}
+}
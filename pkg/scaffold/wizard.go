@@ -0,0 +1,58 @@
+package scaffold
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/suzuki-shunsuke/cmdx/pkg/prompt"
+)
+
+const multiSelectPromptType = "multi_select"
+
+// Select asks which starter tasks are added to the configuration file.
+// If yes is true, the default tasks are selected without asking.
+func Select(candidates []Candidate, yes bool) ([]Candidate, error) {
+	if yes {
+		selected := []Candidate{}
+		for _, c := range candidates {
+			if c.Default {
+				selected = append(selected, c)
+			}
+		}
+		return selected, nil
+	}
+	labels := make([]string, len(candidates))
+	defaults := []string{}
+	m := make(map[string]Candidate, len(candidates))
+	for i, c := range candidates {
+		label := c.Label()
+		labels[i] = label
+		m[label] = c
+		if c.Default {
+			defaults = append(defaults, label)
+		}
+	}
+	p := prompt.Create(prompt.Prompt{
+		Type:    multiSelectPromptType,
+		Message: "Select tasks added to the configuration file",
+		Help:    "Tasks are proposed based on the detected project files",
+		Options: labels,
+	})
+	if ms, ok := p.(*survey.MultiSelect); ok {
+		ms.Default = defaults
+	}
+	v, err := prompt.GetValue(p, multiSelectPromptType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select tasks. If you want to accept the default tasks, please set --yes: %w", err)
+	}
+	answers, ok := v.([]string)
+	if !ok {
+		return nil, errors.New("the answer of the prompt must be []string")
+	}
+	selected := make([]Candidate, len(answers))
+	for i, a := range answers {
+		selected[i] = m[a]
+	}
+	return selected, nil
+}
@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// RunFunc runs the task specified by args.
+// args is the task path and the command line arguments of the task such as ["admin", "cluster", "create"].
+type RunFunc func(ctx context.Context, args []string) error
+
+// Scheduler runs task dependencies.
+// Each dependency is run at most once per invocation.
+// If jobs is greater than 1, independent dependencies are run in parallel,
+// and the number of running scripts is limited to jobs.
+type Scheduler struct {
+	run       RunFunc
+	mutex     sync.Mutex
+	results   map[string]*result
+	jobs      int
+	semaphore chan struct{}
+}
+
+type result struct {
+	done chan struct{}
+	err  error
+}
+
+type prefixKey struct{}
+
+func New(run RunFunc, jobs int) *Scheduler {
+	if jobs < 1 {
+		jobs = 1
+	}
+	return &Scheduler{
+		run:       run,
+		results:   map[string]*result{},
+		jobs:      jobs,
+		semaphore: make(chan struct{}, jobs),
+	}
+}
+
+// OutputPrefix returns the prefix of the output of the task.
+// The prefix is set if the task is run in parallel with other tasks, so that the output of tasks can be distinguished.
+func OutputPrefix(ctx context.Context) string {
+	s, _ := ctx.Value(prefixKey{}).(string)
+	return s
+}
+
+// Acquire waits until the script can be run.
+// The returned function must be called when the script finishes.
+func (sched *Scheduler) Acquire(ctx context.Context) (func(), error) {
+	select {
+	case sched.semaphore <- struct{}{}:
+		return func() {
+			<-sched.semaphore
+		}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Run runs dependencies.
+// Dependencies which have already been run aren't run again.
+// If jobs is 1, dependencies are run in order.
+// Otherwise, dependencies are run in parallel and the first error is returned.
+func (sched *Scheduler) Run(ctx context.Context, names []string) error {
+	if sched.jobs == 1 {
+		for _, name := range names {
+			if err := sched.runOnce(ctx, strings.Fields(name)); err != nil {
+				return fmt.Errorf("failed to run the dependency %s: %w", name, err)
+			}
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for _, name := range names {
+		wg.Go(func() {
+			path := strings.Fields(name)
+			c := context.WithValue(ctx, prefixKey{}, "["+strings.Join(path, " ")+"] ")
+			if err := sched.runOnce(c, path); err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("failed to run the dependency %s: %w", name, err)
+					cancel()
+				})
+			}
+		})
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// RunTask runs the task specified by args.
+// Unlike dependencies, the task is run even if it has already been run.
+func (sched *Scheduler) RunTask(ctx context.Context, args []string) error {
+	return sched.run(ctx, args)
+}
+
+func (sched *Scheduler) runOnce(ctx context.Context, path []string) error {
+	key := strings.Join(path, " ")
+	sched.mutex.Lock()
+	if r, ok := sched.results[key]; ok {
+		sched.mutex.Unlock()
+		select {
+		case <-r.done:
+			return r.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	r := &result{
+		done: make(chan struct{}),
+	}
+	sched.results[key] = r
+	sched.mutex.Unlock()
+
+	r.err = sched.run(ctx, path)
+	close(r.done)
+	return r.err
+}
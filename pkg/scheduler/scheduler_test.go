@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_Run(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title   string
+		names   []string
+		failure string
+		exp     []string
+		isErr   bool
+	}{
+		{
+			title: "run each dependency once",
+			names: []string{"foo", "bar", "foo", "admin  create"},
+			exp:   []string{"foo", "bar", "admin create"},
+		},
+		{
+			title:   "stop at the failure",
+			names:   []string{"foo", "bar", "zoo"},
+			failure: "bar",
+			exp:     []string{"foo", "bar"},
+			isErr:   true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			called := []string{}
+			sched := New(func(_ context.Context, args []string) error {
+				name := strings.Join(args, " ")
+				called = append(called, name)
+				if name == d.failure {
+					return errors.New("failure")
+				}
+				return nil
+			}, 1)
+			err := sched.Run(t.Context(), d.names)
+			if d.isErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, d.exp, called)
+		})
+	}
+}
+
+func TestScheduler_Run_parallel(t *testing.T) {
+	t.Parallel()
+	var (
+		mutex    sync.Mutex
+		running  int
+		maxCount int
+	)
+	prefixes := map[string]string{}
+	var sched *Scheduler
+	sched = New(func(ctx context.Context, args []string) error {
+		release, err := sched.Acquire(ctx)
+		if err != nil {
+			return err
+		}
+		defer release()
+		mutex.Lock()
+		prefixes[strings.Join(args, " ")] = OutputPrefix(ctx)
+		running++
+		maxCount = max(maxCount, running)
+		mutex.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+		return nil
+	}, 2)
+	require.NoError(t, sched.Run(t.Context(), []string{"foo", "bar", "zoo", "foo"}))
+	assert.Equal(t, 2, maxCount)
+	assert.Equal(t, map[string]string{
+		"foo": "[foo] ",
+		"bar": "[bar] ",
+		"zoo": "[zoo] ",
+	}, prefixes)
+}
@@ -3,16 +3,17 @@ package action
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
-	"time"
 
 	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
 	"github.com/suzuki-shunsuke/cmdx/pkg/execute"
 	"github.com/suzuki-shunsuke/cmdx/pkg/flag"
 	"github.com/suzuki-shunsuke/cmdx/pkg/prompt"
 	"github.com/suzuki-shunsuke/cmdx/pkg/requirement"
-	"github.com/suzuki-shunsuke/cmdx/pkg/tmpl"
+	"github.com/suzuki-shunsuke/cmdx/pkg/scheduler"
 	"github.com/suzuki-shunsuke/cmdx/pkg/validate"
+	"github.com/suzuki-shunsuke/cmdx/pkg/variable"
 	"github.com/urfave/cli/v2"
 )
 
@@ -21,14 +22,53 @@ const (
 	builtinKeyArgsString    = "args_string"
 	builtinKeyAllArgs       = "all_args"
 	builtinKeyAllArgsString = "all_args_string"
+	builtinKeyEnv           = "env"
+	builtinKeyProfile       = "profile"
 )
 
+// NewCommandAction returns the action of the task.
+// cfgHooks are the configuration level hooks, which are run around the task and its dependencies by the default shell.
+// evaluator evaluates variables referred by the task.
 func NewCommandAction(
-	task domain.Task, gFlags *domain.GlobalFlags, scriptEnvs map[string][]string,
+	task domain.Task, gFlags *domain.GlobalFlags, scriptEnvs map[string][]string, sched *scheduler.Scheduler, cfgHooks domain.Hooks,
+	evaluator *variable.Evaluator,
 ) cli.ActionFunc {
-	return func(c *cli.Context) error {
+	return func(c *cli.Context) (err error) {
+		// run dependencies
 		// create vars and envs
 		// run command
+		quiet := false
+		if gFlags.Quiet != nil {
+			quiet = *gFlags.Quiet
+		} else if task.Quiet != nil {
+			quiet = *task.Quiet
+		}
+
+		r := &runner{
+			name:         taskPath(c),
+			exc:          execute.New(),
+			sched:        sched,
+			task:         &task,
+			gFlags:       gFlags,
+			vars:         map[string]any{},
+			envs:         appendEnvironment(os.Environ(), task.Environment),
+			quiet:        quiet,
+			outputPrefix: scheduler.OutputPrefix(c.Context),
+		}
+
+		defer func() {
+			err = r.runFinally(c.Context, cfgHooks.Finally, nil, err)
+		}()
+		if err := r.runHook(c.Context, hookBefore, cfgHooks.Before, nil); err != nil {
+			return err
+		}
+
+		if len(task.DependsOn) != 0 {
+			if err := sched.Run(c.Context, task.DependsOn); err != nil {
+				return err
+			}
+		}
+
 		requireChecker := requirement.New()
 		for _, requires := range task.Require.Exec {
 			if err := requireChecker.Exec(requires); err != nil {
@@ -53,41 +93,76 @@ func NewCommandAction(
 			return err
 		}
 
-		// update environment variables which are set to script
-		envs := bindScriptEnvs(os.Environ(), vars, scriptEnvs)
-
-		for k, v := range task.Environment {
-			envs = append(envs, k+"="+v)
+		// set variables which are referred by the task
+		// flags and positional arguments take precedence over variables
+		if evaluator != nil {
+			vals, err := evaluator.Resolve(c.Context, r.name, task.Vars, gFlags.WorkingDir, taskTemplates(&task))
+			if err != nil {
+				return err
+			}
+			for k, v := range vals {
+				if _, ok := vars[k]; !ok {
+					vars[k] = v
+				}
+			}
 		}
 
-		scr, err := tmpl.RenderTemplate(task.Script, vars)
+		// update environment variables which are set to script
+		// environment variables of env files are overridden by script_envs and environment
+		fileEnvs, err := readEnvFiles(gFlags.ConfigDir, task.EnvFiles)
 		if err != nil {
-			return fmt.Errorf("failed to parse the script - %s: %w", task.Script, err)
+			return err
 		}
-
-		quiet := false
-		if gFlags.Quiet != nil {
-			quiet = *gFlags.Quiet
-		} else if task.Quiet != nil {
-			quiet = *task.Quiet
+		envs := bindScriptEnvs(append(os.Environ(), fileEnvs...), vars, scriptEnvs)
+
+		r.envs = appendEnvironment(envs, task.Environment)
+		r.fileEnvs = fileEnvs
+		setBuiltinEnv(vars, r.envs)
+		setBuiltinProfile(vars, gFlags.Profile)
+		r.vars = vars
+		if err := r.run(c.Context); err != nil {
+			return err
 		}
+		return r.runHook(c.Context, hookAfter, cfgHooks.After, nil)
+	}
+}
 
-		exc := execute.New()
-
-		return exc.Run(
-			c.Context, &execute.Params{
-				Shell:      task.Shell,
-				Script:     scr,
-				WorkingDir: gFlags.WorkingDir,
-				Envs:       envs,
-				Timeout: &execute.Timeout{
-					Duration:  time.Duration(task.Timeout.Duration) * time.Second,
-					KillAfter: time.Duration(task.Timeout.KillAfter) * time.Second,
-				},
-				Quiet:  quiet,
-				DryRun: gFlags.DryRun,
-			})
+// setBuiltinEnv sets environment variables passed to the script to the template variable "_builtin.env".
+func setBuiltinEnv(vars map[string]any, envs []string) {
+	if builtin, ok := vars["_builtin"].(map[string]any); ok {
+		builtin[builtinKeyEnv] = envMap(envs)
+	}
+}
+
+// setBuiltinProfile sets the name of the active profile to the template variable "_builtin.profile".
+func setBuiltinProfile(vars map[string]any, profile string) {
+	if builtin, ok := vars["_builtin"].(map[string]any); ok {
+		builtin[builtinKeyProfile] = profile
+	}
+}
+
+func appendEnvironment(envs []string, environment map[string]string) []string {
+	for k, v := range environment {
+		envs = append(envs, k+"="+v)
+	}
+	return envs
+}
+
+// taskPath returns the space separated path of the task such as "admin cluster create".
+func taskPath(c *cli.Context) string {
+	names := []string{}
+	for _, ctx := range c.Lineage() {
+		if ctx.Command != nil {
+			names = append(names, ctx.Command.Name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
 	}
+	// The last command is the root command of the app.
+	names = names[:len(names)-1]
+	slices.Reverse(names)
+	return strings.Join(names, " ")
 }
 
 func updateVarsByArgs(
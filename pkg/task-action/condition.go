@@ -0,0 +1,42 @@
+package action
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/tmpl"
+)
+
+// isTruthy returns false if the rendered condition is falsy.
+// "", "false", "0", "no", "off", and "<no value>" are falsy.
+func isTruthy(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "false", "0", "no", "off", "<no value>":
+		return false
+	}
+	return true
+}
+
+// checkCondition renders the condition with template variables and returns whether it's truthy.
+// If the condition is empty, it returns true.
+func (r *runner) checkCondition(cond string) (bool, error) {
+	if cond == "" {
+		return true, nil
+	}
+	s, err := tmpl.RenderTemplate(cond, r.vars)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse the condition - %s: %w", cond, err)
+	}
+	return isTruthy(s), nil
+}
+
+// envMap converts the list of environment variables "KEY=VALUE" to the map.
+// If the same key is set multiple times, the last value is used.
+func envMap(envs []string) map[string]string {
+	m := make(map[string]string, len(envs))
+	for _, env := range envs {
+		k, v, _ := strings.Cut(env, "=")
+		m[k] = v
+	}
+	return m
+}
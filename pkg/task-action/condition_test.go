@@ -0,0 +1,70 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_runner_checkCondition(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		cond  string
+		vars  map[string]any
+		exp   bool
+		isErr bool
+	}{
+		{
+			title: "empty",
+			exp:   true,
+		},
+		{
+			title: "true",
+			cond:  "{{.notify}}",
+			vars:  map[string]any{"notify": true},
+			exp:   true,
+		},
+		{
+			title: "false",
+			cond:  "{{.notify}}",
+			vars:  map[string]any{"notify": false},
+		},
+		{
+			title: "no value",
+			cond:  "{{.notify}}",
+			vars:  map[string]any{},
+		},
+		{
+			title: "environment variable",
+			cond:  `{{eq ._builtin.env.STAGE "prod"}}`,
+			vars: map[string]any{"_builtin": map[string]any{
+				"env": envMap([]string{"STAGE=dev", "STAGE=prod"}),
+			}},
+			exp: true,
+		},
+		{
+			title: "falsy strings",
+			cond:  " Off ",
+		},
+		{
+			title: "invalid template",
+			cond:  "{{",
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			r := &runner{vars: d.vars}
+			ok, err := r.checkCondition(d.cond)
+			if d.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, ok)
+		})
+	}
+}
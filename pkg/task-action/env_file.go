@@ -0,0 +1,44 @@
+package action
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/dotenv"
+)
+
+// readEnvFiles reads dotenv files and returns environment variables "KEY=VALUE".
+// The relative path is relative to dir.
+// Variables of later files take precedence, and later files can refer variables of earlier files.
+func readEnvFiles(dir string, files []domain.EnvFile) ([]string, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	env := map[string]string{}
+	for _, file := range files {
+		p := file.Path
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		if err := dotenv.ReadFile(p, env, os.LookupEnv); err != nil {
+			if file.Optional && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+	}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	envs := make([]string, len(keys))
+	for i, k := range keys {
+		envs[i] = k + "=" + env[k]
+	}
+	return envs, nil
+}
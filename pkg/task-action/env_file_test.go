@@ -0,0 +1,60 @@
+package action
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+func Test_readEnvFiles(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		files []domain.EnvFile
+		exp   []string
+		isErr bool
+	}{
+		{
+			title: "later files take precedence",
+			files: []domain.EnvFile{
+				{Path: ".env"},
+				{Path: ".env.local", Optional: true},
+			},
+			exp: []string{"FOO=local", "URL=http://localhost:8080"},
+		},
+		{
+			title: "missing optional file",
+			files: []domain.EnvFile{
+				{Path: ".env"},
+				{Path: ".env.test", Optional: true},
+			},
+			exp: []string{"FOO=foo", "URL=http://localhost:8080"},
+		},
+		{
+			title: "missing required file",
+			files: []domain.EnvFile{
+				{Path: ".env.test"},
+			},
+			isErr: true,
+		},
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("FOO=foo\nURL=http://localhost:8080\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env.local"), []byte("FOO=local\n"), 0o600))
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			envs, err := readEnvFiles(dir, d.files)
+			if d.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, envs)
+		})
+	}
+}
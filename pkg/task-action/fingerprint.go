@@ -0,0 +1,61 @@
+package action
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/fingerprint"
+)
+
+const stateDirName = ".cmdx"
+
+func (r *runner) fingerprintChecker() *fingerprint.Checker {
+	return fingerprint.New(filepath.Join(r.gFlags.ConfigDir, stateDirName))
+}
+
+// checkFingerprint returns the fingerprint of the task's sources and whether the sources are up to date.
+// If task.sources isn't set, the sources are treated as up to date.
+// If --force is set, the sources are treated as outdated.
+func (r *runner) checkFingerprint() (string, bool, error) {
+	if len(r.task.Sources) == 0 {
+		return "", !r.gFlags.Force, nil
+	}
+	sum, err := fingerprint.Sum(r.gFlags.WorkingDir, r.task.Sources, r.fingerprintSeed())
+	if err != nil {
+		return "", false, fmt.Errorf("failed to compute the fingerprint of sources: %w", err)
+	}
+	if r.gFlags.Force {
+		return sum, false, nil
+	}
+	upToDate, err := r.fingerprintChecker().IsUpToDate(r.name, sum, r.gFlags.WorkingDir, r.task.Generates)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to check the fingerprint: %w", err)
+	}
+	return sum, upToDate, nil
+}
+
+// fingerprintSeed returns the settings of the task which are included in the fingerprint,
+// so the task is run if they are changed.
+// The script, values of flags, positional arguments, and variables, environment, and env_files are included.
+// Environment variables of the process "_builtin.env" are excluded
+// because unrelated environment variables such as GITHUB_RUN_ID would make the task always outdated.
+func (r *runner) fingerprintSeed() string {
+	vars := maps.Clone(r.vars)
+	if builtin, ok := vars["_builtin"].(map[string]any); ok {
+		builtin = maps.Clone(builtin)
+		delete(builtin, builtinKeyEnv)
+		vars["_builtin"] = builtin
+	}
+	return fmt.Sprint(r.task.Script, r.task.Steps, vars, r.task.Environment, r.fileEnvs)
+}
+
+func (r *runner) saveFingerprint(sum string) error {
+	if sum == "" || r.gFlags.DryRun {
+		return nil
+	}
+	if err := r.fingerprintChecker().Save(r.name, sum); err != nil {
+		return fmt.Errorf("failed to save the fingerprint: %w", err)
+	}
+	return nil
+}
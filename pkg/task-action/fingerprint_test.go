@@ -0,0 +1,84 @@
+package action
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+func Test_runner_checkFingerprint(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title  string
+		update func(r *runner)
+		exp    bool
+	}{
+		{
+			title:  "nothing is changed",
+			update: func(*runner) {},
+			exp:    true,
+		},
+		{
+			title: "an unrelated environment variable is changed",
+			update: func(r *runner) {
+				r.vars["_builtin"] = map[string]any{
+					builtinKeyEnv: map[string]string{"GITHUB_RUN_ID": "2"},
+				}
+			},
+			exp: true,
+		},
+		{
+			title: "the flag is changed",
+			update: func(r *runner) {
+				r.vars["target"] = "prod"
+			},
+		},
+		{
+			title: "environment is changed",
+			update: func(r *runner) {
+				r.task.Environment = map[string]string{"STAGE": "prod"}
+			},
+		},
+		{
+			title: "the env file is changed",
+			update: func(r *runner) {
+				r.fileEnvs = []string{"FOO=bar"}
+			},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "in.txt"), []byte("hello"), 0o600))
+			r := &runner{
+				name: "gen",
+				task: &domain.Task{
+					Script:      "cat in.txt",
+					Sources:     []string{"in.txt"},
+					Environment: map[string]string{"STAGE": "dev"},
+				},
+				gFlags: &domain.GlobalFlags{WorkingDir: dir, ConfigDir: dir},
+				vars: map[string]any{
+					"target": "dev",
+					"_builtin": map[string]any{
+						builtinKeyEnv: map[string]string{"GITHUB_RUN_ID": "1"},
+					},
+				},
+				fileEnvs: []string{"FOO=foo"},
+			}
+			sum, upToDate, err := r.checkFingerprint()
+			require.NoError(t, err)
+			assert.False(t, upToDate)
+			require.NoError(t, r.saveFingerprint(sum))
+			d.update(r)
+			_, upToDate, err = r.checkFingerprint()
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, upToDate)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/tmpl"
+)
+
+var varRefPattern = regexp.MustCompile(`^\{\{-?\s*\.([A-Za-z_][A-Za-z0-9_]*)\s*-?\}\}$`)
+
+// forEachItems returns the list of items of for_each.
+// If the expression refers a list variable directly such as "{{.services}}", the list is used as is.
+// Otherwise, the expression is rendered and split by newlines, or by commas if it has only one line.
+// Empty items are excluded.
+func (r *runner) forEachItems(expr string) ([]string, error) {
+	if m := varRefPattern.FindStringSubmatch(strings.TrimSpace(expr)); m != nil {
+		switch v := r.vars[m[1]].(type) {
+		case []string:
+			return slices.Clone(v), nil
+		case []any:
+			items := make([]string, len(v))
+			for i, a := range v {
+				items[i] = fmt.Sprint(a)
+			}
+			return items, nil
+		}
+	}
+	s, err := tmpl.RenderTemplate(expr, r.vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse for_each - %s: %w", expr, err)
+	}
+	sep := ","
+	if strings.Contains(s, "\n") {
+		sep = "\n"
+	}
+	items := []string{}
+	for item := range strings.SplitSeq(s, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// withItem returns a copy of the runner whose variables and environment variables include the item of for_each.
+func (r *runner) withItem(index int, item string) *runner {
+	c := *r
+	c.vars = make(map[string]any, len(r.vars)+2) //nolint:mnd
+	for k, v := range r.vars {
+		c.vars[k] = v
+	}
+	c.vars["item"] = item
+	c.vars["index"] = index
+	c.envs = append(slices.Clone(r.envs), "CMDX_ITEM="+item, "CMDX_INDEX="+strconv.Itoa(index))
+	return &c
+}
+
+// runForEach runs the script once per item of task.for_each.
+// If an item fails, the remaining items aren't run.
+func (r *runner) runForEach(ctx context.Context) error {
+	if r.task.ForEach == "" {
+		return r.runWithRetry(ctx)
+	}
+	items, err := r.forEachItems(r.task.ForEach)
+	if err != nil {
+		return err
+	}
+	for i, item := range items {
+		if err := r.withItem(i, item).runWithRetry(ctx); err != nil {
+			return fmt.Errorf("failed to run the item %d (%s): %w", i, item, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_runner_forEachItems(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		expr  string
+		vars  map[string]any
+		exp   []string
+		isErr bool
+	}{
+		{
+			title: "list variable",
+			expr:  "{{ .services }}",
+			vars:  map[string]any{"services": []string{"api server", "worker"}},
+			exp:   []string{"api server", "worker"},
+		},
+		{
+			title: "comma separated",
+			expr:  "{{.services}}",
+			vars:  map[string]any{"services": "api, worker,"},
+			exp:   []string{"api", "worker"},
+		},
+		{
+			title: "newline separated",
+			expr:  "{{.services}}",
+			vars:  map[string]any{"services": "api,v1\nworker\n"},
+			exp:   []string{"api,v1", "worker"},
+		},
+		{
+			title: "template",
+			expr:  `{{join "," .services}},db`,
+			vars:  map[string]any{"services": []string{"api", "worker"}},
+			exp:   []string{"api", "worker", "db"},
+		},
+		{
+			title: "empty",
+			expr:  "{{.services}}",
+			vars:  map[string]any{"services": ""},
+			exp:   []string{},
+		},
+		{
+			title: "invalid template",
+			expr:  "{{",
+			isErr: true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			r := &runner{vars: d.vars}
+			items, err := r.forEachItems(d.expr)
+			if d.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, items)
+		})
+	}
+}
+
+func Test_runner_withItem(t *testing.T) {
+	t.Parallel()
+	r := &runner{
+		vars: map[string]any{"foo": "bar"},
+		envs: []string{"FOO=bar"},
+	}
+	c := r.withItem(1, "worker")
+	assert.Equal(t, map[string]any{"foo": "bar", "item": "worker", "index": 1}, c.vars)
+	assert.Equal(t, []string{"FOO=bar", "CMDX_ITEM=worker", "CMDX_INDEX=1"}, c.envs)
+	assert.Equal(t, map[string]any{"foo": "bar"}, r.vars)
+}
@@ -0,0 +1,43 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/tmpl"
+)
+
+const (
+	hookBefore  = "before"
+	hookAfter   = "after"
+	hookFinally = "finally"
+)
+
+// runHook runs the hook script with the task's environment variables and timeout.
+func (r *runner) runHook(ctx context.Context, name, script string, shell []string) error {
+	if script == "" {
+		return nil
+	}
+	scr, err := tmpl.RenderTemplate(script, r.vars)
+	if err != nil {
+		return fmt.Errorf("failed to parse the %s hook - %s: %w", name, script, err)
+	}
+	if err := r.execute(ctx, r.params(shell, scr, r.gFlags.WorkingDir, r.envs, r.task.Timeout)); err != nil {
+		return fmt.Errorf("the %s hook failed: %w", name, err)
+	}
+	return nil
+}
+
+// runFinally runs the finally hook even if the task fails, times out, or is interrupted.
+// The hook isn't cancelled by the cancellation of ctx, but the task's timeout is applied.
+// If both the task and the hook fail, both errors are returned.
+func (r *runner) runFinally(ctx context.Context, script string, shell []string, err error) error {
+	if e := r.runHook(context.WithoutCancel(ctx), hookFinally, script, shell); e != nil {
+		if err == nil {
+			return e
+		}
+		return errors.Join(err, e)
+	}
+	return err
+}
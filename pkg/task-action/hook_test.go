@@ -0,0 +1,88 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/execute"
+	"github.com/suzuki-shunsuke/cmdx/pkg/scheduler"
+)
+
+func Test_runner_run_hooks(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title  string
+		script string
+		isErr  bool
+		exp    string
+	}{
+		{
+			title:  "normal",
+			script: "echo script >> $LOG",
+			exp:    "before\nscript\nafter\nfinally\n",
+		},
+		{
+			title:  "the script fails",
+			script: "exit 1",
+			isErr:  true,
+			exp:    "before\nfinally\n",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			log := filepath.Join(t.TempDir(), "log")
+			r := &runner{
+				exc:   execute.New(),
+				sched: scheduler.New(nil, 1),
+				task: &domain.Task{
+					Script: d.script,
+					Hooks: domain.Hooks{
+						Before:  "echo before >> $LOG",
+						After:   "echo after >> $LOG",
+						Finally: "echo finally >> $LOG",
+					},
+				},
+				gFlags: &domain.GlobalFlags{},
+				vars:   map[string]any{},
+				envs:   []string{"LOG=" + log},
+				quiet:  true,
+			}
+			err := r.run(t.Context())
+			if d.isErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			b, err := os.ReadFile(log)
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, string(b))
+		})
+	}
+}
+
+func Test_runner_runFinally(t *testing.T) {
+	t.Parallel()
+	r := &runner{
+		exc:    execute.New(),
+		sched:  scheduler.New(nil, 1),
+		task:   &domain.Task{},
+		gFlags: &domain.GlobalFlags{},
+		vars:   map[string]any{},
+		quiet:  true,
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	// The finally hook is run even if the context is cancelled.
+	require.NoError(t, r.runFinally(ctx, "true", nil, nil))
+	err := r.runFinally(ctx, "exit 1", nil, errors.New("the task failed"))
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "the task failed\nthe finally hook failed"))
+}
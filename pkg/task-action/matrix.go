@@ -0,0 +1,144 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/suzuki-shunsuke/go-error-with-exit-code/ecerror"
+)
+
+// matrixCell is a combination of matrix values.
+type matrixCell struct {
+	keys   []string
+	values map[string]string
+}
+
+func (cell *matrixCell) String() string {
+	arr := make([]string, len(cell.keys))
+	for i, k := range cell.keys {
+		arr[i] = k + "=" + cell.values[k]
+	}
+	return strings.Join(arr, " ")
+}
+
+// expandMatrix returns all combinations of matrix values.
+// Combinations which don't match filters are excluded.
+// filters are "key=value" and combinations must match all filters.
+// If the key of the filter isn't in the matrix, it returns an error so that typos are noticed.
+func expandMatrix(matrix map[string][]string, filters []string) ([]*matrixCell, error) {
+	keys := make([]string, 0, len(matrix))
+	for k := range matrix {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	only := map[string][]string{}
+	for _, filter := range filters {
+		k, v, ok := strings.Cut(filter, "=")
+		if !ok {
+			return nil, fmt.Errorf("the format of --only must be key=value: %s", filter)
+		}
+		if _, ok := matrix[k]; !ok {
+			return nil, fmt.Errorf("the key %s of --only isn't in the matrix. The keys are %s", k, strings.Join(keys, ", "))
+		}
+		only[k] = append(only[k], v)
+	}
+
+	cells := []*matrixCell{{
+		keys:   keys,
+		values: map[string]string{},
+	}}
+	for _, k := range keys {
+		next := make([]*matrixCell, 0, len(cells)*len(matrix[k]))
+		for _, cell := range cells {
+			for _, v := range matrix[k] {
+				if vals, ok := only[k]; ok && !slices.Contains(vals, v) {
+					continue
+				}
+				values := make(map[string]string, len(cell.values)+1)
+				for a, b := range cell.values {
+					values[a] = b
+				}
+				values[k] = v
+				next = append(next, &matrixCell{
+					keys:   keys,
+					values: values,
+				})
+			}
+		}
+		cells = next
+	}
+	return cells, nil
+}
+
+// runMatrix runs the script once per combination of matrix values.
+// Matrix values are available as template variables and environment variables.
+// All combinations are run even if some of them fail, and the summary is outputted.
+func (r *runner) runMatrix(ctx context.Context) error {
+	if len(r.task.Matrix) == 0 {
+		return r.runForEach(ctx)
+	}
+	cells, err := expandMatrix(r.task.Matrix, r.gFlags.Only)
+	if err != nil {
+		return err
+	}
+	if len(cells) == 0 {
+		r.skip("no matrix combination matches --only")
+		return nil
+	}
+	errs := make([]error, len(cells))
+	var lastErr error
+	failed := []string{}
+	for i, cell := range cells {
+		if !r.quiet {
+			fmt.Fprintln(os.Stderr, r.outputPrefix+"matrix: "+cell.String())
+		}
+		c := r.withMatrixCell(cell)
+		if err := c.runForEach(ctx); err != nil {
+			errs[i] = err
+			lastErr = err
+			failed = append(failed, cell.String())
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if !r.quiet {
+		summary := make([]string, len(cells))
+		for i, cell := range cells {
+			result := "succeeded"
+			if errs[i] != nil {
+				result = "failed (" + errs[i].Error() + ")"
+			}
+			summary[i] = "  " + cell.String() + ": " + result
+		}
+		fmt.Fprintln(os.Stderr, r.outputPrefix+"matrix summary:\n"+strings.Join(summary, "\n"))
+	}
+	if lastErr == nil {
+		return nil
+	}
+	// The failed combinations are included in the error because the summary isn't outputted with --quiet.
+	return ecerror.Wrap(
+		errors.New(strconv.Itoa(len(failed))+" of "+strconv.Itoa(len(cells))+" matrix combinations failed: "+strings.Join(failed, ", ")),
+		ecerror.GetExitCode(lastErr))
+}
+
+// withMatrixCell returns a copy of the runner whose variables and environment variables include matrix values.
+func (r *runner) withMatrixCell(cell *matrixCell) *runner {
+	c := *r
+	c.vars = make(map[string]any, len(r.vars)+len(cell.values))
+	for k, v := range r.vars {
+		c.vars[k] = v
+	}
+	c.envs = slices.Clone(r.envs)
+	for _, k := range cell.keys {
+		c.vars[k] = cell.values[k]
+		c.envs = append(c.envs, strings.ToUpper(strings.ReplaceAll(k, "-", "_"))+"="+cell.values[k])
+	}
+	return &c
+}
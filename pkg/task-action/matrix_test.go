@@ -0,0 +1,105 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/execute"
+	"github.com/suzuki-shunsuke/cmdx/pkg/scheduler"
+	"github.com/suzuki-shunsuke/go-error-with-exit-code/ecerror"
+)
+
+func Test_expandMatrix(t *testing.T) {
+	t.Parallel()
+	matrix := map[string][]string{
+		"goos":   {"linux", "darwin"},
+		"goarch": {"amd64", "arm64"},
+	}
+	data := []struct {
+		title   string
+		filters []string
+		exp     []string
+		isErr   bool
+		errMsg  string
+	}{
+		{
+			title: "all combinations",
+			exp: []string{
+				"goarch=amd64 goos=linux",
+				"goarch=amd64 goos=darwin",
+				"goarch=arm64 goos=linux",
+				"goarch=arm64 goos=darwin",
+			},
+		},
+		{
+			title:   "filter",
+			filters: []string{"goos=linux"},
+			exp: []string{
+				"goarch=amd64 goos=linux",
+				"goarch=arm64 goos=linux",
+			},
+		},
+		{
+			title:   "multiple values of the same key",
+			filters: []string{"goos=linux", "goarch=arm64", "goos=darwin"},
+			exp: []string{
+				"goarch=arm64 goos=linux",
+				"goarch=arm64 goos=darwin",
+			},
+		},
+		{
+			title:   "unknown key",
+			filters: []string{"gos=linux"},
+			isErr:   true,
+			errMsg:  "the key gos of --only isn't in the matrix. The keys are goarch, goos",
+		},
+		{
+			title:   "invalid filter",
+			filters: []string{"goos"},
+			isErr:   true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			cells, err := expandMatrix(matrix, d.filters)
+			if d.isErr {
+				require.Error(t, err)
+				if d.errMsg != "" {
+					assert.EqualError(t, err, d.errMsg)
+				}
+				return
+			}
+			require.NoError(t, err)
+			names := make([]string, len(cells))
+			for i, cell := range cells {
+				names[i] = cell.String()
+			}
+			assert.Equal(t, d.exp, names)
+		})
+	}
+}
+
+func Test_runner_runMatrix(t *testing.T) {
+	t.Parallel()
+	r := &runner{
+		exc:   execute.New(),
+		sched: scheduler.New(nil, 1),
+		task: &domain.Task{
+			Matrix: map[string][]string{
+				"goos":   {"linux", "darwin", "windows"},
+				"goarch": {"amd64"},
+			},
+			Script: `test "{{.goos}}" = linux || exit 3`,
+		},
+		gFlags: &domain.GlobalFlags{},
+		vars:   map[string]any{},
+		quiet:  true,
+	}
+	err := r.runMatrix(t.Context())
+	require.Error(t, err)
+	assert.Equal(t, "2 of 3 matrix combinations failed: goarch=amd64 goos=darwin, goarch=amd64 goos=windows", err.Error())
+	assert.Equal(t, 3, ecerror.GetExitCode(err))
+}
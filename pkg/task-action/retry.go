@@ -0,0 +1,53 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/go-error-with-exit-code/ecerror"
+)
+
+const (
+	backoffExponential = "exponential"
+	envAttempt         = "CMDX_ATTEMPT"
+)
+
+func shouldRetry(retry domain.Retry, err error) bool {
+	if len(retry.OnExitCodes) == 0 {
+		return true
+	}
+	return slices.Contains(retry.OnExitCodes, ecerror.GetExitCode(err))
+}
+
+// runWithRetry runs the script until it succeeds or the number of attempts reaches task.retry.attempts.
+// The attempt number is set to the environment variable CMDX_ATTEMPT.
+// If all attempts fail, the error of the last attempt is returned.
+func (r *runner) runWithRetry(ctx context.Context) error {
+	retry := r.task.Retry
+	attempts := max(retry.Attempts, 1)
+	delay := time.Duration(retry.Delay) * time.Second
+	for attempt := 1; ; attempt++ {
+		c := *r
+		c.envs = append(slices.Clone(r.envs), envAttempt+"="+strconv.Itoa(attempt))
+		err := c.runScript(ctx)
+		if err == nil || attempt >= attempts || ctx.Err() != nil || !shouldRetry(retry, err) {
+			return err
+		}
+		if !r.quiet {
+			fmt.Fprintf(os.Stderr, "%sthe attempt %d/%d failed: %v. retry after %s\n", r.outputPrefix, attempt, attempts, err, delay)
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		if retry.Backoff == backoffExponential {
+			delay *= 2
+		}
+	}
+}
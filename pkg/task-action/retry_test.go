@@ -0,0 +1,87 @@
+package action
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/execute"
+	"github.com/suzuki-shunsuke/cmdx/pkg/scheduler"
+	"github.com/suzuki-shunsuke/go-error-with-exit-code/ecerror"
+)
+
+func Test_runner_runWithRetry(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title    string
+		script   string
+		retry    domain.Retry
+		exitCode int
+		exp      string
+	}{
+		{
+			title:  "succeed at the third attempt",
+			script: `echo $CMDX_ATTEMPT >> $LOG; test "$CMDX_ATTEMPT" = 3`,
+			retry: domain.Retry{
+				Attempts: 3,
+			},
+			exp: "1\n2\n3\n",
+		},
+		{
+			title:  "all attempts fail",
+			script: `echo $CMDX_ATTEMPT >> $LOG; exit $((CMDX_ATTEMPT + 1))`,
+			retry: domain.Retry{
+				Attempts: 2,
+				Backoff:  "exponential",
+			},
+			exitCode: 3,
+			exp:      "1\n2\n",
+		},
+		{
+			title:  "exit code doesn't match",
+			script: `echo $CMDX_ATTEMPT >> $LOG; exit 2`,
+			retry: domain.Retry{
+				Attempts:    3,
+				OnExitCodes: []int{1, 75},
+			},
+			exitCode: 2,
+			exp:      "1\n",
+		},
+		{
+			title:  "no retry",
+			script: `echo $CMDX_ATTEMPT >> $LOG`,
+			exp:    "1\n",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			log := filepath.Join(t.TempDir(), "log")
+			r := &runner{
+				exc:   execute.New(),
+				sched: scheduler.New(nil, 1),
+				task: &domain.Task{
+					Script: d.script,
+					Retry:  d.retry,
+				},
+				gFlags: &domain.GlobalFlags{},
+				vars:   map[string]any{},
+				envs:   []string{"LOG=" + log},
+				quiet:  true,
+			}
+			err := r.runWithRetry(t.Context())
+			if d.exitCode == 0 {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Equal(t, d.exitCode, ecerror.GetExitCode(err))
+			}
+			b, err := os.ReadFile(log)
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, string(b))
+		})
+	}
+}
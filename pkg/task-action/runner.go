@@ -0,0 +1,122 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/execute"
+	"github.com/suzuki-shunsuke/cmdx/pkg/scheduler"
+	"github.com/suzuki-shunsuke/cmdx/pkg/tmpl"
+)
+
+// runner runs the script or steps of the task.
+type runner struct {
+	name   string
+	exc    *execute.Executor
+	sched  *scheduler.Scheduler
+	task   *domain.Task
+	gFlags *domain.GlobalFlags
+	vars   map[string]any
+	envs   []string
+	// fileEnvs are environment variables read from env_files.
+	fileEnvs     []string
+	quiet        bool
+	outputPrefix string
+}
+
+func (r *runner) run(ctx context.Context) (err error) {
+	ok, err := r.checkCondition(r.task.If)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		r.skip("the condition isn't satisfied: " + r.task.If)
+		return nil
+	}
+	sum, upToDate, err := r.isUpToDate(ctx)
+	if err != nil {
+		return err
+	}
+	if upToDate {
+		r.skip("the task is up to date")
+		return nil
+	}
+	defer func() {
+		err = r.runFinally(ctx, r.task.Finally, r.task.Shell, err)
+	}()
+	if err := r.runHook(ctx, hookBefore, r.task.Before, r.task.Shell); err != nil {
+		return err
+	}
+	if err := r.runMatrix(ctx); err != nil {
+		return err
+	}
+	if err := r.runHook(ctx, hookAfter, r.task.After, r.task.Shell); err != nil {
+		return err
+	}
+	return r.saveFingerprint(sum)
+}
+
+// isUpToDate returns the fingerprint of the task's sources and whether the task is up to date.
+// The task is up to date if task.sources or task.status is set and all of them are up to date.
+func (r *runner) isUpToDate(ctx context.Context) (string, bool, error) {
+	if len(r.task.Sources) == 0 && len(r.task.Status) == 0 {
+		return "", false, nil
+	}
+	sum, upToDate, err := r.checkFingerprint()
+	if err != nil || !upToDate {
+		return sum, false, err
+	}
+	upToDate, err = r.checkStatus(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	return sum, upToDate, nil
+}
+
+// skip outputs the reason why the task is skipped.
+func (r *runner) skip(reason string) {
+	if !r.quiet {
+		fmt.Fprintln(os.Stderr, r.outputPrefix+"skip "+r.name+": "+reason)
+	}
+}
+
+func (r *runner) runScript(ctx context.Context) error {
+	if len(r.task.Steps) != 0 {
+		return r.runSteps(ctx)
+	}
+	scr, err := tmpl.RenderTemplate(r.task.Script, r.vars)
+	if err != nil {
+		return fmt.Errorf("failed to parse the script - %s: %w", r.task.Script, err)
+	}
+	return r.execute(ctx, r.params(r.task.Shell, scr, r.gFlags.WorkingDir, r.envs, r.task.Timeout))
+}
+
+// execute runs the script.
+// The number of scripts run at the same time is limited by the scheduler.
+func (r *runner) execute(ctx context.Context, params *execute.Params) error {
+	release, err := r.sched.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return r.exc.Run(ctx, params)
+}
+
+func (r *runner) params(shell []string, script, workingDir string, envs []string, timeout domain.Timeout) *execute.Params {
+	return &execute.Params{
+		Shell:      shell,
+		Script:     script,
+		WorkingDir: workingDir,
+		Envs:       envs,
+		Timeout: &execute.Timeout{
+			Duration:  time.Duration(timeout.Duration) * time.Second,
+			KillAfter: time.Duration(timeout.KillAfter) * time.Second,
+		},
+		Quiet:        r.quiet,
+		DryRun:       r.gFlags.DryRun,
+		OutputPrefix: r.outputPrefix,
+	}
+}
@@ -0,0 +1,38 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/tmpl"
+)
+
+// checkStatus runs task.status and returns true if all of them exit with 0.
+// The output of status commands is discarded.
+// If --dry-run or --force is set, status commands aren't run and the task is treated as outdated.
+func (r *runner) checkStatus(ctx context.Context) (bool, error) {
+	if len(r.task.Status) == 0 {
+		return true, nil
+	}
+	if r.gFlags.DryRun || r.gFlags.Force {
+		return false, nil
+	}
+	for _, status := range r.task.Status {
+		scr, err := tmpl.RenderTemplate(status, r.vars)
+		if err != nil {
+			return false, fmt.Errorf("failed to parse the status - %s: %w", status, err)
+		}
+		params := r.params(r.task.Shell, scr, r.gFlags.WorkingDir, r.envs, r.task.Timeout)
+		params.Quiet = true
+		params.Silent = true
+		if err := r.execute(ctx, params); err != nil {
+			if exitErr := (&exec.ExitError{}); errors.As(err, &exitErr) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to run the status command - %s: %w", status, err)
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,67 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/execute"
+	"github.com/suzuki-shunsuke/cmdx/pkg/scheduler"
+)
+
+func Test_runner_checkStatus(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title  string
+		status []string
+		gFlags *domain.GlobalFlags
+		exp    bool
+	}{
+		{
+			title: "status isn't set",
+			exp:   true,
+		},
+		{
+			title:  "all status succeed",
+			status: []string{"true", `test "{{.foo}}" = foo`},
+			exp:    true,
+		},
+		{
+			title:  "status fails",
+			status: []string{"true", "exit 1"},
+		},
+		{
+			title:  "dry run",
+			status: []string{"true"},
+			gFlags: &domain.GlobalFlags{DryRun: true},
+		},
+		{
+			title:  "force",
+			status: []string{"true"},
+			gFlags: &domain.GlobalFlags{Force: true},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			if d.gFlags == nil {
+				d.gFlags = &domain.GlobalFlags{}
+			}
+			r := &runner{
+				exc:   execute.New(),
+				sched: scheduler.New(nil, 1),
+				task: &domain.Task{
+					Status: d.status,
+				},
+				gFlags: d.gFlags,
+				vars: map[string]any{
+					valFoo: valFoo,
+				},
+			}
+			upToDate, err := r.checkStatus(t.Context())
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, upToDate)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/tmpl"
+)
+
+func stepName(i int, step domain.Step) string {
+	s := strconv.Itoa(i + 1)
+	if step.Name != "" {
+		s += " (" + step.Name + ")"
+	}
+	return s
+}
+
+// runSteps runs steps in order.
+// If a step fails, the remaining steps aren't run unless the step's ignore_error is true.
+func (r *runner) runSteps(ctx context.Context) error {
+	for i, step := range r.task.Steps {
+		name := stepName(i, step)
+		if step.ForEach == "" {
+			if err := r.runStepIf(ctx, name, step); err != nil {
+				return err
+			}
+			continue
+		}
+		items, err := r.forEachItems(step.ForEach)
+		if err != nil {
+			return fmt.Errorf("the step %s failed: %w", name, err)
+		}
+		for j, item := range items {
+			if err := r.withItem(j, item).runStepIf(ctx, name+" ["+item+"]", step); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runStepIf runs the step if the step's condition is satisfied.
+func (r *runner) runStepIf(ctx context.Context, name string, step domain.Step) error {
+	ok, err := r.checkCondition(step.If)
+	if err != nil {
+		return fmt.Errorf("the step %s failed: %w", name, err)
+	}
+	if !ok {
+		if !r.quiet {
+			fmt.Fprintln(os.Stderr, r.outputPrefix+"skip the step "+name+": the condition isn't satisfied: "+step.If)
+		}
+		return nil
+	}
+	if err := r.runStep(ctx, step); err != nil {
+		if step.IgnoreError {
+			if !r.quiet {
+				fmt.Fprintf(os.Stderr, "%sthe step %s failed but the error is ignored: %v\n", r.outputPrefix, name, err)
+			}
+			return nil
+		}
+		return fmt.Errorf("the step %s failed: %w", name, err)
+	}
+	return nil
+}
+
+func (r *runner) runStep(ctx context.Context, step domain.Step) error {
+	if step.Task != "" {
+		return r.runTaskStep(ctx, step)
+	}
+
+	scr, err := tmpl.RenderTemplate(step.Script, r.vars)
+	if err != nil {
+		return fmt.Errorf("failed to parse the script - %s: %w", step.Script, err)
+	}
+
+	shell := step.Shell
+	if len(shell) == 0 {
+		shell = r.task.Shell
+	}
+
+	workingDir := r.gFlags.WorkingDir
+	if step.Dir != "" {
+		if filepath.IsAbs(step.Dir) {
+			workingDir = step.Dir
+		} else {
+			workingDir = filepath.Join(workingDir, step.Dir)
+		}
+	}
+
+	envs := r.envs
+	if len(step.Environment) != 0 {
+		envs = make([]string, len(r.envs), len(r.envs)+len(step.Environment))
+		copy(envs, r.envs)
+		for k, v := range step.Environment {
+			envs = append(envs, k+"="+v)
+		}
+	}
+
+	timeout := step.Timeout
+	if timeout.Duration == 0 {
+		timeout.Duration = r.task.Timeout.Duration
+	}
+	if timeout.KillAfter == 0 {
+		timeout.KillAfter = r.task.Timeout.KillAfter
+	}
+
+	return r.execute(ctx, r.params(shell, scr, workingDir, envs, timeout))
+}
+
+// runTaskStep runs another task in-process.
+// The flags and positional arguments are passed as command line arguments,
+// so they are validated in the same way as the command line.
+func (r *runner) runTaskStep(ctx context.Context, step domain.Step) error {
+	args := strings.Fields(step.Task)
+	names := make([]string, 0, len(step.Flags))
+	for name := range step.Flags {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		v, err := tmpl.RenderTemplate(step.Flags[name], r.vars)
+		if err != nil {
+			return fmt.Errorf("failed to parse the flag %s - %s: %w", name, step.Flags[name], err)
+		}
+		args = append(args, "--"+name+"="+v)
+	}
+	if len(step.Args) != 0 {
+		args = append(args, "--")
+		for _, arg := range step.Args {
+			v, err := tmpl.RenderTemplate(arg, r.vars)
+			if err != nil {
+				return fmt.Errorf("failed to parse the argument - %s: %w", arg, err)
+			}
+			args = append(args, v)
+		}
+	}
+	if err := r.sched.RunTask(ctx, args); err != nil {
+		return fmt.Errorf("failed to run the task %s: %w", step.Task, err)
+	}
+	return nil
+}
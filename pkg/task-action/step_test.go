@@ -0,0 +1,155 @@
+package action
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/execute"
+	"github.com/suzuki-shunsuke/cmdx/pkg/scheduler"
+)
+
+func Test_runner_runSteps(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		steps []domain.Step
+		exp   string
+	}{
+		{
+			title: "normal",
+			steps: []domain.Step{
+				{
+					Script: "true",
+				},
+				{
+					Script:      `test "$FOO" = foo`,
+					Environment: map[string]string{"FOO": valFoo},
+				},
+			},
+		},
+		{
+			title: "ignore error",
+			steps: []domain.Step{
+				{
+					Script:      "false",
+					IgnoreError: true,
+				},
+				{
+					Script: "true",
+				},
+			},
+		},
+		{
+			title: "failure",
+			steps: []domain.Step{
+				{
+					Script: "true",
+				},
+				{
+					Name:   "build",
+					Script: "exit 3",
+				},
+				{
+					Script: "true",
+				},
+			},
+			exp: "the step 2 (build) failed: exit status 3",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			r := &runner{
+				exc:   execute.New(),
+				sched: scheduler.New(nil, 1),
+				task: &domain.Task{
+					Steps: d.steps,
+				},
+				gFlags: &domain.GlobalFlags{},
+				vars:   map[string]any{},
+				quiet:  true,
+			}
+			err := r.runSteps(t.Context())
+			if d.exp == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, d.exp)
+		})
+	}
+}
+
+func Test_runner_runTaskStep(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title  string
+		step   domain.Step
+		dryRun bool
+		err    error
+		exp    []string
+		expErr string
+	}{
+		{
+			title: "flags and args",
+			step: domain.Step{
+				Task:  "deploy",
+				Flags: map[string]string{"env": "{{.stage}}", "dry": "true"},
+				Args:  []string{"web", "{{.region}}"},
+			},
+			exp: []string{"deploy", "--dry=true", "--env=prod", "--", "web", "us"},
+		},
+		{
+			title: "subcommand",
+			step: domain.Step{
+				Task: "admin cluster create",
+			},
+			exp: []string{"admin", "cluster", "create"},
+		},
+		{
+			title: "dry run",
+			step: domain.Step{
+				Task:  "deploy",
+				Flags: map[string]string{"env": "prod"},
+			},
+			// The called task is run with the same global flags, so it outputs the script instead of running it.
+			dryRun: true,
+			exp:    []string{"deploy", "--env=prod"},
+		},
+		{
+			title: "the called task fails",
+			step: domain.Step{
+				Task: "deploy",
+			},
+			err:    errors.New("exit status 1"),
+			exp:    []string{"deploy"},
+			expErr: "failed to run the task deploy: exit status 1",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			var args []string
+			r := &runner{
+				sched: scheduler.New(func(_ context.Context, a []string) error {
+					args = a
+					return d.err
+				}, 1),
+				task:   &domain.Task{},
+				gFlags: &domain.GlobalFlags{DryRun: d.dryRun},
+				vars:   map[string]any{"stage": "prod", "region": "us"},
+				quiet:  true,
+			}
+			err := r.runTaskStep(t.Context(), d.step)
+			assert.Equal(t, d.exp, args)
+			if d.expErr != "" {
+				require.EqualError(t, err, d.expErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package action
+
+import (
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+// taskTemplates returns templates of the task, which are used to find variables referred by the task.
+func taskTemplates(task *domain.Task) []string {
+	templates := []string{task.Script, task.If, task.ForEach, task.Before, task.After, task.Finally}
+	templates = append(templates, task.Status...)
+	for _, step := range task.Steps {
+		templates = append(templates, step.Script, step.If, step.ForEach)
+		for _, v := range step.Flags {
+			templates = append(templates, v)
+		}
+		templates = append(templates, step.Args...)
+	}
+	return templates
+}
@@ -0,0 +1,130 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+// dependencyName normalizes the name of a dependency.
+// A sub task is referred by the space separated path such as "admin cluster create".
+func dependencyName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+func collectTasks(tasks []domain.Task, parent string, m map[string]domain.Task) {
+	for _, task := range tasks {
+		name := task.Name
+		if parent != "" {
+			name = parent + " " + name
+		}
+		m[name] = task
+		collectTasks(task.Tasks, name, m)
+	}
+}
+
+func vDependencies(tasks []domain.Task) error {
+	m := map[string]domain.Task{}
+	collectTasks(tasks, "", m)
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	graph := make(map[string][]string, len(m))
+	errs := []error{}
+	for _, name := range names {
+		task := m[name]
+		deps := make([]string, 0, len(task.DependsOn)+len(task.Steps))
+		for i, dep := range task.DependsOn {
+			depName := dependencyName(dep)
+			t, ok := m[depName]
+			if !ok {
+				errs = append(errs, withPosition(task, fmt.Errorf(`the dependency isn't found: task: "%s", depends_on: "%s"`, name, dep), "depends_on", i))
+				continue
+			}
+			if len(t.Tasks) != 0 {
+				errs = append(errs, withPosition(task, fmt.Errorf(`the task which has sub tasks can't be a dependency: task: "%s", depends_on: "%s"`, name, dep), "depends_on", i))
+				continue
+			}
+			deps = append(deps, depName)
+		}
+		for i, step := range task.Steps {
+			if step.Task == "" {
+				continue
+			}
+			depName := dependencyName(step.Task)
+			t, ok := m[depName]
+			if !ok {
+				errs = append(errs, withPosition(task, fmt.Errorf(`the step's task isn't found: task: "%s", step: %d, step.task: "%s"`, name, i+1, step.Task), "steps", i, "task"))
+				continue
+			}
+			if len(t.Tasks) != 0 {
+				errs = append(errs, withPosition(task, fmt.Errorf(`the task which has sub tasks can't be run as a step: task: "%s", step: %d, step.task: "%s"`, name, i+1, step.Task), "steps", i, "task"))
+				continue
+			}
+			if err := vStepFlags(name, i, step, t); err != nil {
+				errs = append(errs, withPosition(task, err, "steps", i, "flags"))
+			}
+			deps = append(deps, depName)
+		}
+		graph[name] = deps
+	}
+	if err := vCycle(names, graph); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func vStepFlags(taskName string, i int, step domain.Step, task domain.Task) error {
+	for name := range step.Flags {
+		if !slices.ContainsFunc(task.Flags, func(flag domain.Flag) bool {
+			return flag.Name == name
+		}) {
+			return fmt.Errorf(`the flag isn't defined in the step's task: task: "%s", step: %d, step.task: "%s", flag: "%s"`, taskName, i+1, step.Task, name)
+		}
+	}
+	return nil
+}
+
+const (
+	visiting = iota + 1
+	visited
+)
+
+// vCycle returns an error if the dependency graph has a cycle.
+// The error message includes the path of the cycle such as "a -> b -> a".
+func vCycle(names []string, graph map[string][]string) error {
+	states := make(map[string]int, len(graph))
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch states[name] {
+		case visited:
+			return nil
+		case visiting:
+			for i, n := range path {
+				if n == name {
+					return errors.New("the task dependency is circular: " + strings.Join(append(path[i:], name), " -> "))
+				}
+			}
+		}
+		states[name] = visiting
+		path = append(path, name)
+		for _, dep := range graph[name] {
+			if err := visit(dep, path); err != nil {
+				return err
+			}
+		}
+		states[name] = visited
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+func Test_vDependencies(t *testing.T) {
+	data := []struct {
+		title string
+		tasks []domain.Task
+		exp   string
+	}{
+		{
+			title: testTitleNormal,
+			tasks: []domain.Task{
+				{
+					Name:      testValFoo,
+					DependsOn: []string{testValBar, "admin  create"},
+				},
+				{
+					Name: testValBar,
+				},
+				{
+					Name: "admin",
+					Tasks: []domain.Task{
+						{
+							Name:      "create",
+							DependsOn: []string{testValBar},
+						},
+					},
+				},
+			},
+		},
+		{
+			title: "dependency isn't found",
+			tasks: []domain.Task{
+				{
+					Name:      testValFoo,
+					DependsOn: []string{testValBar},
+				},
+			},
+			exp: `the dependency isn't found: task: "foo", depends_on: "bar"`,
+		},
+		{
+			title: "dependency has sub tasks",
+			tasks: []domain.Task{
+				{
+					Name:      testValFoo,
+					DependsOn: []string{testValBar},
+				},
+				{
+					Name: testValBar,
+					Tasks: []domain.Task{
+						{
+							Name: testValHello,
+						},
+					},
+				},
+			},
+			exp: `the task which has sub tasks can't be a dependency: task: "foo", depends_on: "bar"`,
+		},
+		{
+			title: "cycle",
+			tasks: []domain.Task{
+				{
+					Name:      testValFoo,
+					DependsOn: []string{testValBar},
+				},
+				{
+					Name:      testValBar,
+					DependsOn: []string{testValHello},
+				},
+				{
+					Name:      testValHello,
+					DependsOn: []string{testValFoo},
+				},
+			},
+			exp: "the task dependency is circular: bar -> hello -> foo -> bar",
+		},
+		{
+			title: "step's task isn't found",
+			tasks: []domain.Task{
+				{
+					Name: testValFoo,
+					Steps: []domain.Step{
+						{
+							Task: testValBar,
+						},
+					},
+				},
+			},
+			exp: `the step's task isn't found: task: "foo", step: 1, step.task: "bar"`,
+		},
+		{
+			title: "step's flag isn't defined",
+			tasks: []domain.Task{
+				{
+					Name: testValFoo,
+					Steps: []domain.Step{
+						{
+							Task: testValBar,
+							Flags: map[string]string{
+								"env": "prod",
+							},
+						},
+					},
+				},
+				{
+					Name: testValBar,
+				},
+			},
+			exp: `the flag isn't defined in the step's task: task: "foo", step: 1, step.task: "bar", flag: "env"`,
+		},
+		{
+			title: "step cycle",
+			tasks: []domain.Task{
+				{
+					Name: testValFoo,
+					Steps: []domain.Step{
+						{
+							Task: testValBar,
+						},
+					},
+				},
+				{
+					Name:      testValBar,
+					DependsOn: []string{testValFoo},
+				},
+			},
+			exp: "the task dependency is circular: bar -> foo -> bar",
+		},
+		{
+			title: "self dependency",
+			tasks: []domain.Task{
+				{
+					Name:      testValFoo,
+					DependsOn: []string{testValFoo},
+				},
+			},
+			exp: "the task dependency is circular: foo -> foo",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			err := vDependencies(d.tasks)
+			if d.exp == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, d.exp)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package validate
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"go.yaml.in/yaml/v3"
+)
+
+// Error is a validation error with the position of the invalid setting.
+type Error struct {
+	File   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		if e.File == "" {
+			return e.Err.Error()
+		}
+		return e.File + ": " + e.Err.Error()
+	}
+	return domain.Position(e.File, e.Line, e.Column) + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// keyError is an error of the setting specified by path such as ["validate", 0, "regexp"].
+// path is relative to the node which is validated.
+type keyError struct {
+	path []any
+	err  error
+}
+
+func (e *keyError) Error() string {
+	return e.err.Error()
+}
+
+func (e *keyError) Unwrap() error {
+	return e.err
+}
+
+func atKey(err error, path ...any) error {
+	return &keyError{
+		path: path,
+		err:  err,
+	}
+}
+
+// withPosition returns the error with the position of the setting of the task specified by path.
+// If err joins multiple errors, each error gets the position.
+// Errors which already have positions are returned as is.
+func withPosition(task domain.Task, err error, path ...any) error {
+	switch e := err.(type) { //nolint:errorlint
+	case nil:
+		return nil
+	case *Error:
+		return e
+	case *keyError:
+		return withPosition(task, e.err, append(slices.Clone(path), e.path...)...)
+	case interface{ Unwrap() []error }:
+		errs := e.Unwrap()
+		arr := make([]error, len(errs))
+		for i, err := range errs {
+			arr[i] = withPosition(task, err, path...)
+		}
+		return errors.Join(arr...)
+	}
+	node := lookupNode(task.Node, path)
+	if node == nil {
+		if task.Source == "" {
+			return err
+		}
+		return &Error{File: task.Source, Err: err}
+	}
+	return &Error{
+		File:   task.Source,
+		Line:   node.Line,
+		Column: node.Column,
+		Err:    err,
+	}
+}
+
+// lookupNode returns the node specified by path.
+// If the node isn't found, the nearest ancestor node is returned.
+func lookupNode(node *yaml.Node, path []any) *yaml.Node {
+	for _, p := range path {
+		if node == nil {
+			return nil
+		}
+		var child *yaml.Node
+		switch key := p.(type) {
+		case string:
+			if node.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(node.Content); i += 2 {
+					if node.Content[i].Value == key {
+						child = node.Content[i+1]
+						break
+					}
+				}
+			}
+		case int:
+			if node.Kind == yaml.SequenceNode && key < len(node.Content) {
+				child = node.Content[key]
+			}
+		}
+		if child == nil {
+			return node
+		}
+		node = child
+	}
+	return node
+}
@@ -0,0 +1,127 @@
+package validate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/prompt"
+	"go.yaml.in/yaml/v3"
+)
+
+func taskNode(t *testing.T, s string) *yaml.Node {
+	t.Helper()
+	node := &yaml.Node{}
+	require.NoError(t, yaml.Unmarshal([]byte(s), node))
+	return node.Content[0]
+}
+
+func TestConfig_positions(t *testing.T) {
+	t.Parallel()
+	node := taskNode(t, `name: foo
+flags:
+  - name: a
+    type: int
+  - name: b
+    prompt:
+      type: foo
+    validate:
+      - regexp: "a("
+depends_on:
+  - zzz
+`)
+	cfg := &domain.Config{
+		Tasks: []domain.Task{
+			{
+				Name:   testValFoo,
+				Source: ".cmdx.yaml",
+				Node:   node,
+				Flags: []domain.Flag{
+					{Name: "a", Type: "int"},
+					{
+						Name:     "b",
+						Prompt:   prompt.Prompt{Type: "foo"},
+						Validate: []domain.Validate{{RegExp: "a("}},
+					},
+				},
+				DependsOn: []string{"zzz"},
+			},
+		},
+	}
+	err := Config(cfg)
+	require.Error(t, err)
+	exp := []string{
+		`.cmdx.yaml:4:11: the flag type should be either '' or 'string' or 'bool'. task: foo, flag: a, flag.type: int`,
+		`.cmdx.yaml:7:13: the flag prompt type is invalid: task: foo, flag: b, prompt: foo`,
+		".cmdx.yaml:9:17: the regular expression is invalid: task: foo, name: b, validate.regexp: a(: error parsing regexp: missing closing ): `a(`",
+		`.cmdx.yaml:11:5: the dependency isn't found: task: "foo", depends_on: "zzz"`,
+	}
+	assert.Equal(t, strings.Join(exp, "\n"), err.Error())
+}
+
+func Test_withPosition(t *testing.T) {
+	t.Parallel()
+	node := taskNode(t, `name: foo
+args:
+  - name: a
+`)
+	data := []struct {
+		title string
+		task  domain.Task
+		err   error
+		path  []any
+		exp   string
+	}{
+		{
+			title: "nil",
+		},
+		{
+			title: "no node",
+			task:  domain.Task{Source: ".cmdx.yaml"},
+			err:   errors.New("foo"),
+			path:  []any{"name"},
+			exp:   ".cmdx.yaml: foo",
+		},
+		{
+			title: "key",
+			task:  domain.Task{Source: ".cmdx.yaml", Node: node},
+			err:   atKey(errors.New("foo"), "name"),
+			path:  []any{"args", 0},
+			exp:   ".cmdx.yaml:3:11: foo",
+		},
+		{
+			title: "nearest node",
+			task:  domain.Task{Source: ".cmdx.yaml", Node: node},
+			err:   errors.New("foo"),
+			path:  []any{"args", 1, "name"},
+			exp:   ".cmdx.yaml:3:3: foo",
+		},
+		{
+			title: "jsonnet",
+			task:  domain.Task{Source: ".cmdx.jsonnet", Node: node},
+			err:   atKey(errors.New("foo"), "name"),
+			path:  []any{"args", 0},
+			exp:   ".cmdx.jsonnet (evaluated JSON):3:11: foo",
+		},
+		{
+			title: "joined",
+			task:  domain.Task{Source: ".cmdx.yaml", Node: node},
+			err:   errors.Join(atKey(errors.New("foo"), "name"), errors.New("bar")),
+			exp:   ".cmdx.yaml:1:7: foo\n.cmdx.yaml:1:1: bar",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			err := withPosition(d.task, d.err, d.path...)
+			if d.exp == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.Equal(t, d.exp, err.Error())
+		})
+	}
+}
@@ -3,6 +3,9 @@ package validate
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
 
 	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
 )
@@ -17,24 +20,95 @@ var flagTypes = map[string]struct{}{ //nolint:gochecknoglobals
 	"editor":       {},
 }
 
+// Config validates the configuration.
+// All errors are returned with the positions of invalid settings.
 func Config(cfg *domain.Config) error {
-	taskNames := make(map[string]struct{}, len(cfg.Tasks))
-	taskShortNames := make(map[string]struct{}, len(cfg.Tasks))
+	errs := []error{}
+	if cfg.Concurrency < 0 {
+		errs = append(errs, errors.New("concurrency must be greater than or equal to 0"))
+	}
+	for _, name := range sortedKeys(cfg.Vars) {
+		if err := vVarName(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	taskNames := make(map[string]domain.Task, len(cfg.Tasks))
+	taskShortNames := make(map[string]domain.Task, len(cfg.Tasks))
 	for _, task := range cfg.Tasks {
-		if !vUniqueName(task.Name, taskNames) {
-			return errors.New(`the task name duplicates: "` + task.Name + `"`)
+		if t, ok := taskNames[task.Name]; ok {
+			errs = append(errs, withPosition(task, errors.New(`the task name duplicates: "`+task.Name+`"`+duplicateSources(t, task)), "name"))
+		} else {
+			taskNames[task.Name] = task
 		}
 
 		if task.Short != "" {
-			if !vUniqueName(task.Short, taskShortNames) {
-				return errors.New(`the task short name duplicates: "` + task.Short + `"`)
+			if t, ok := taskShortNames[task.Short]; ok {
+				errs = append(errs, withPosition(task, errors.New(`the task short name duplicates: "`+task.Short+`"`+duplicateSources(t, task)), "short"))
+			} else {
+				taskShortNames[task.Short] = task
+			}
+		}
+		errs = append(errs, vTask(task))
+	}
+	errs = append(errs, vDependencies(cfg.Tasks))
+	errs = append(errs, vProfiles(cfg))
+	return errors.Join(errs...)
+}
+
+// vProfiles validates profiles.
+// Flags of profiles must be defined by tasks to find typos.
+// Values of bool flags must be true or false.
+func vProfiles(cfg *domain.Config) error {
+	// flag name -> true if the flag is a bool flag in any task
+	flagNames := map[string]bool{}
+	var collect func(tasks []domain.Task)
+	collect = func(tasks []domain.Task) {
+		for _, task := range tasks {
+			for _, flag := range task.Flags {
+				flagNames[flag.Name] = flagNames[flag.Name] || flag.Type == "bool"
 			}
+			collect(task.Tasks)
 		}
-		if err := vTask(task); err != nil {
-			return err
+	}
+	collect(cfg.Tasks)
+	errs := []error{}
+	for _, name := range sortedKeys(cfg.Profiles) {
+		flags := cfg.Profiles[name].Flags
+		for _, flag := range sortedKeys(flags) {
+			isBool, ok := flagNames[flag]
+			if !ok {
+				errs = append(errs, fmt.Errorf("the flag isn't defined by any task: profile: %s, flag: %s", name, flag))
+				continue
+			}
+			if _, err := strconv.ParseBool(flags[flag]); isBool && err != nil {
+				errs = append(errs, fmt.Errorf("the value of the bool flag must be true or false: profile: %s, flag: %s, value: %s", name, flag, flags[flag]))
+			}
 		}
 	}
-	return nil
+	return errors.Join(errs...)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// duplicateSources returns the files where duplicated tasks are defined.
+func duplicateSources(a, b domain.Task) string {
+	if a.Source == "" && b.Source == "" {
+		return ""
+	}
+	if a.Source == b.Source {
+		if a.Node == nil || a.Node.Line == 0 {
+			return ""
+		}
+		return fmt.Sprintf(": the first task is defined at line %d", a.Node.Line)
+	}
+	return ": " + a.Source + ", " + b.Source
 }
 
 func vUniqueName(name string, names map[string]struct{}) bool {
@@ -46,26 +120,35 @@ func vUniqueName(name string, names map[string]struct{}) bool {
 }
 
 func vFlag(taskName string, flag domain.Flag, flagNames, flagShortNames map[string]struct{}) error {
+	errs := []error{}
 	if flag.Name == "" {
-		return errors.New("the flag name is required: task: " + taskName)
+		errs = append(errs, errors.New("the flag name is required: task: "+taskName))
 	}
 	if len(flag.Short) > 1 {
-		return fmt.Errorf(
+		errs = append(errs, atKey(fmt.Errorf(
 			"the length of task.short should be 0 or 1. task: %s, flag: %s, short: %s",
-			taskName, flag.Name, flag.Short)
+			taskName, flag.Name, flag.Short), "short"))
+	}
+
+	if flag.Type == "bool" && flag.Default != "" {
+		if _, err := strconv.ParseBool(flag.Default); err != nil {
+			errs = append(errs, atKey(fmt.Errorf(
+				"the default value of the bool flag must be true or false: task: %s, flag: %s, default: %s",
+				taskName, flag.Name, flag.Default), "default"))
+		}
 	}
 
-	if !vUniqueName(flag.Name, flagNames) {
-		return fmt.Errorf(
+	if flag.Name != "" && !vUniqueName(flag.Name, flagNames) {
+		errs = append(errs, atKey(fmt.Errorf(
 			`the flag name duplicates: task: "%s", flag: "%s"`,
-			taskName, flag.Name)
+			taskName, flag.Name), "name"))
 	}
 
 	if flag.Short != "" {
 		if !vUniqueName(flag.Short, flagShortNames) {
-			return fmt.Errorf(
+			errs = append(errs, atKey(fmt.Errorf(
 				`the flag short name duplicates: task: "%s", flag.short: "%s"`,
-				taskName, flag.Short)
+				taskName, flag.Short), "short"))
 		}
 	}
 
@@ -74,60 +157,248 @@ func vFlag(taskName string, flag domain.Flag, flagNames, flagShortNames map[stri
 	case "bool":
 	case "string":
 	default:
-		return fmt.Errorf(
+		errs = append(errs, atKey(fmt.Errorf(
 			"the flag type should be either '' or 'string' or 'bool'. task: %s, flag: %s, flag.type: %s",
-			taskName, flag.Name, flag.Type)
+			taskName, flag.Name, flag.Type), "type"))
 	}
 
 	if flag.Prompt.Type != "" {
 		if _, ok := flagTypes[flag.Prompt.Type]; !ok {
-			return fmt.Errorf(
+			errs = append(errs, atKey(fmt.Errorf(
 				"the flag prompt type is invalid: task: %s, flag: %s, prompt: %s",
-				taskName, flag.Name, flag.Prompt.Type)
+				taskName, flag.Name, flag.Prompt.Type), "prompt", "type"))
 		}
 	}
 
-	return nil
+	errs = append(errs, vValidates(taskName, flag.Name, flag.Validate))
+	return errors.Join(errs...)
+}
+
+// vValidates validates the type and regular expression of `validate`.
+func vValidates(taskName, name string, validates []domain.Validate) error {
+	errs := []error{}
+	for i, v := range validates {
+		switch v.Type {
+		case "", validateTypeEmail, validateTypeURL, validateTypeInt:
+		default:
+			errs = append(errs, atKey(fmt.Errorf(
+				"the validate type should be either 'email', 'url', or 'int': task: %s, name: %s, validate.type: %s",
+				taskName, name, v.Type), "validate", i, "type"))
+		}
+		if v.RegExp != "" {
+			if _, err := regexp.Compile(v.RegExp); err != nil {
+				errs = append(errs, atKey(fmt.Errorf(
+					"the regular expression is invalid: task: %s, name: %s, validate.regexp: %s: %w",
+					taskName, name, v.RegExp, err), "validate", i, "regexp"))
+			}
+		}
+	}
+	return errors.Join(errs...)
 }
 
 func vArg(taskName string, arg domain.Arg, argNames map[string]struct{}) error {
+	errs := []error{}
 	if arg.Name == "" {
-		return errors.New("the positional argument name is required: task: " + taskName)
-	}
-	if !vUniqueName(arg.Name, argNames) {
-		return fmt.Errorf(
+		errs = append(errs, errors.New("the positional argument name is required: task: "+taskName))
+	} else if !vUniqueName(arg.Name, argNames) {
+		errs = append(errs, atKey(fmt.Errorf(
 			`the positional argument name duplicates: task: "%s", arg: "%s"`,
-			taskName, arg.Name)
+			taskName, arg.Name), "name"))
+	}
+	if arg.Prompt.Type != "" {
+		if _, ok := flagTypes[arg.Prompt.Type]; !ok {
+			errs = append(errs, atKey(fmt.Errorf(
+				"the positional argument prompt type is invalid: task: %s, arg: %s, prompt: %s",
+				taskName, arg.Name, arg.Prompt.Type), "prompt", "type"))
+		}
+	}
+	errs = append(errs, vValidates(taskName, arg.Name, arg.Validate))
+	return errors.Join(errs...)
+}
+
+func vStep(taskName string, i int, step domain.Step) error {
+	if step.Task == "" {
+		if step.Script == "" {
+			return fmt.Errorf("either the step's script or task is required: task: %s, step: %d", taskName, i+1)
+		}
+		if len(step.Flags) != 0 || len(step.Args) != 0 {
+			return fmt.Errorf("the step's flags and args can be set only when the step's task is set: task: %s, step: %d", taskName, i+1)
+		}
+		return nil
+	}
+	if step.Script != "" {
+		return atKey(fmt.Errorf("the step's script and task can't be set at the same time: task: %s, step: %d", taskName, i+1), "script")
+	}
+	return nil
+}
+
+func vMatrix(task domain.Task) error {
+	if len(task.Matrix) == 0 {
+		return nil
+	}
+	if len(task.Tasks) != 0 {
+		return errors.New("the task `" + task.Name + "` is invalid. when sub tasks are set, 'matrix' can't be set")
+	}
+	errs := []error{}
+	for _, k := range sortedKeys(task.Matrix) {
+		if k == "" {
+			errs = append(errs, errors.New("the matrix key is required: task: "+task.Name))
+			continue
+		}
+		if len(task.Matrix[k]) == 0 {
+			errs = append(errs, atKey(fmt.Errorf("the matrix values are required: task: %s, matrix: %s", task.Name, k), k))
+		}
+		for _, flag := range task.Flags {
+			if flag.Name == k {
+				errs = append(errs, atKey(fmt.Errorf("the matrix key duplicates with the flag name: task: %s, matrix: %s", task.Name, k), k))
+			}
+		}
+		for _, arg := range task.Args {
+			if arg.Name == k {
+				errs = append(errs, atKey(fmt.Errorf("the matrix key duplicates with the positional argument name: task: %s, matrix: %s", task.Name, k), k))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func vVarName(name string) error {
+	if name == "" {
+		return errors.New("the variable name is required")
+	}
+	if name == "_builtin" {
+		return errors.New("the variable name '_builtin' is reserved")
 	}
 	return nil
 }
 
+// vVars validates task level variables.
+// Flags and positional arguments take precedence over variables, so task level variables can't have the same names.
+func vVars(task domain.Task) error {
+	errs := []error{}
+	for _, name := range sortedKeys(task.Vars) {
+		if err := vVarName(name); err != nil {
+			errs = append(errs, fmt.Errorf("%w: task: %s", err, task.Name))
+			continue
+		}
+		for _, flag := range task.Flags {
+			if flag.Name == name {
+				errs = append(errs, atKey(fmt.Errorf("the variable name duplicates with the flag name: task: %s, variable: %s", task.Name, name), name))
+			}
+		}
+		for _, arg := range task.Args {
+			if arg.Name == name {
+				errs = append(errs, atKey(fmt.Errorf("the variable name duplicates with the positional argument name: task: %s, variable: %s", task.Name, name), name))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// vForEach validates for_each.
+// The template variables "item" and "index" are set by for_each, so flags and positional arguments can't use these names.
+func vForEach(task domain.Task) error {
+	hasForEach := task.ForEach != ""
+	for _, step := range task.Steps {
+		if step.ForEach != "" {
+			hasForEach = true
+		}
+	}
+	if !hasForEach {
+		return nil
+	}
+	if len(task.Tasks) != 0 {
+		return atKey(errors.New("the task `"+task.Name+"` is invalid. when sub tasks are set, 'for_each' can't be set"), "for_each")
+	}
+	errs := []error{}
+	for i, flag := range task.Flags {
+		if flag.Name == "item" || flag.Name == "index" {
+			errs = append(errs, atKey(fmt.Errorf("the flag name can't be 'item' and 'index' when for_each is set: task: %s, flag: %s", task.Name, flag.Name), "flags", i, "name"))
+		}
+	}
+	for i, arg := range task.Args {
+		if arg.Name == "item" || arg.Name == "index" {
+			errs = append(errs, atKey(fmt.Errorf("the positional argument name can't be 'item' and 'index' when for_each is set: task: %s, arg: %s", task.Name, arg.Name), "args", i, "name"))
+		}
+	}
+	for _, k := range sortedKeys(task.Matrix) {
+		if k == "item" || k == "index" {
+			errs = append(errs, atKey(fmt.Errorf("the matrix key can't be 'item' and 'index' when for_each is set: task: %s, matrix: %s", task.Name, k), "matrix", k))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func vRetry(task domain.Task) error {
+	retry := task.Retry
+	errs := []error{}
+	if retry.Attempts < 0 {
+		errs = append(errs, atKey(errors.New("retry.attempts must be greater than or equal to 0: task: "+task.Name), "attempts"))
+	}
+	if retry.Delay < 0 {
+		errs = append(errs, atKey(errors.New("retry.delay must be greater than or equal to 0: task: "+task.Name), "delay"))
+	}
+	switch retry.Backoff {
+	case "", "constant", "exponential":
+	default:
+		errs = append(errs, atKey(fmt.Errorf("retry.backoff should be either 'constant' or 'exponential': task: %s, backoff: %s", task.Name, retry.Backoff), "backoff"))
+	}
+	return errors.Join(errs...)
+}
+
+// vTask validates the task and its sub tasks.
+// All errors are returned with the positions of invalid settings.
 func vTask(task domain.Task) error {
+	errs := []error{}
+	add := func(err error, path ...any) {
+		if err != nil {
+			errs = append(errs, withPosition(task, err, path...))
+		}
+	}
 	if task.Name == "" {
-		return errors.New("the task name is required")
+		add(errors.New("the task name is required"))
 	}
 	flagNames := make(map[string]struct{}, len(task.Flags))
 	flagShortNames := make(map[string]struct{}, len(task.Flags))
-	for _, flag := range task.Flags {
-		if err := vFlag(task.Name, flag, flagNames, flagShortNames); err != nil {
-			return err
-		}
+	for i, flag := range task.Flags {
+		add(vFlag(task.Name, flag, flagNames, flagShortNames), "flags", i)
 	}
 	argNames := make(map[string]struct{}, len(task.Args))
-	for _, arg := range task.Args {
-		if err := vArg(task.Name, arg, argNames); err != nil {
-			return err
-		}
+	for i, arg := range task.Args {
+		add(vArg(task.Name, arg, argNames), "args", i)
 	}
 	if len(task.Tasks) != 0 {
 		if task.Script != "" {
-			return errors.New("the task `" + task.Name + "` is invalid. when sub tasks are set, 'script' can't be set")
+			add(errors.New("the task `"+task.Name+"` is invalid. when sub tasks are set, 'script' can't be set"), "script")
+		}
+		if len(task.Args) != 0 {
+			add(errors.New("the task `"+task.Name+"` is invalid. when sub tasks are set, 'args' can't be set"), "args")
+		}
+		if len(task.DependsOn) != 0 {
+			add(errors.New("the task `"+task.Name+"` is invalid. when sub tasks are set, 'depends_on' can't be set"), "depends_on")
+		}
+		if len(task.Steps) != 0 {
+			add(errors.New("the task `"+task.Name+"` is invalid. when sub tasks are set, 'steps' can't be set"), "steps")
 		}
+		if task.Hooks != (domain.Hooks{}) {
+			add(errors.New("the task `" + task.Name + "` is invalid. when sub tasks are set, 'before', 'after', and 'finally' can't be set"))
+		}
+	}
+	add(vVars(task), "vars")
+	add(vForEach(task))
+	if len(task.Steps) != 0 && task.Script != "" {
+		add(errors.New("the task `"+task.Name+"` is invalid. when steps are set, 'script' can't be set"), "script")
+	}
+	add(vMatrix(task), "matrix")
+	add(vRetry(task), "retry")
+	if len(task.Generates) != 0 && len(task.Sources) == 0 {
+		add(errors.New("the task `"+task.Name+"` is invalid. when 'generates' is set, 'sources' is required"), "generates")
+	}
+	for i, step := range task.Steps {
+		add(vStep(task.Name, i, step), "steps", i)
 	}
 	for _, t := range task.Tasks {
-		if err := vTask(t); err != nil {
-			return err
-		}
+		errs = append(errs, vTask(t))
 	}
-	return nil
+	return errors.Join(errs...)
 }
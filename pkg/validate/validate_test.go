@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"go.yaml.in/yaml/v3"
 )
 
 const (
@@ -123,6 +124,38 @@ func TestConfig(t *testing.T) {
 			},
 			isErr: true,
 		},
+		{
+			title: "the flag of the profile isn't defined",
+			cfg: &domain.Config{
+				Tasks: []domain.Task{
+					{
+						Name:   testValFoo,
+						Script: testValPwd,
+						Flags:  []domain.Flag{{Name: "region"}},
+					},
+				},
+				Profiles: map[string]domain.Profile{
+					"staging": {Flags: map[string]string{"regin": "us-east-1"}},
+				},
+			},
+			isErr: true,
+		},
+		{
+			title: "the value of the bool flag of the profile isn't bool",
+			cfg: &domain.Config{
+				Tasks: []domain.Task{
+					{
+						Name:   testValFoo,
+						Script: testValPwd,
+						Flags:  []domain.Flag{{Name: "verbose", Type: "bool"}},
+					},
+				},
+				Profiles: map[string]domain.Profile{
+					"prod": {Flags: map[string]string{"verbose": "yes"}},
+				},
+			},
+			isErr: true,
+		},
 		{
 			title: "invalid task",
 			cfg: &domain.Config{
@@ -203,6 +236,15 @@ func Test_vFlag(t *testing.T) {
 			},
 			isErr: true,
 		},
+		{
+			title: "the default value of the bool flag isn't bool",
+			flag: domain.Flag{
+				Name:    testValFoo,
+				Type:    "bool",
+				Default: "yes",
+			},
+			isErr: true,
+		},
 		{
 			title: testTitleNormal,
 			flag: domain.Flag{
@@ -307,6 +349,148 @@ func Test_vTask(t *testing.T) {
 			},
 			isErr: true,
 		},
+		{
+			title: "both sub tasks and args are set",
+			task: domain.Task{
+				Name: testValFoo,
+				Args: []domain.Arg{
+					{Name: testValBar},
+				},
+				Tasks: []domain.Task{
+					{Name: testValBar, Script: testValPwd},
+				},
+			},
+			isErr: true,
+		},
+		{
+			title: "both steps and script are set",
+			task: domain.Task{
+				Name:   testValFoo,
+				Script: testValPwd,
+				Steps: []domain.Step{
+					{
+						Script: testValPwd,
+					},
+				},
+			},
+			isErr: true,
+		},
+		{
+			title: "step script is required",
+			task: domain.Task{
+				Name: testValFoo,
+				Steps: []domain.Step{
+					{
+						Name: testValBar,
+					},
+				},
+			},
+			isErr: true,
+		},
+		{
+			title: "both step's script and task are set",
+			task: domain.Task{
+				Name: testValFoo,
+				Steps: []domain.Step{
+					{
+						Script: testValPwd,
+						Task:   testValBar,
+					},
+				},
+			},
+			isErr: true,
+		},
+		{
+			title: "step's flags are set without task",
+			task: domain.Task{
+				Name: testValFoo,
+				Steps: []domain.Step{
+					{
+						Script: testValPwd,
+						Args:   []string{testValBar},
+					},
+				},
+			},
+			isErr: true,
+		},
+		{
+			title: "variable duplicates with the flag",
+			task: domain.Task{
+				Name:   testValFoo,
+				Script: "echo {{.version}}",
+				Flags: []domain.Flag{
+					{
+						Name: "version",
+					},
+				},
+				Vars: map[string]domain.Var{
+					"version": {Value: "v1.0.0"},
+				},
+			},
+			isErr: true,
+		},
+		{
+			title: "for_each",
+			task: domain.Task{
+				Name:    testValFoo,
+				ForEach: "{{.services}}",
+				Script:  "echo {{.item}}",
+			},
+		},
+		{
+			title: "for_each and the flag item",
+			task: domain.Task{
+				Name: testValFoo,
+				Flags: []domain.Flag{
+					{
+						Name: "item",
+					},
+				},
+				Steps: []domain.Step{
+					{
+						Script:  "echo {{.item}}",
+						ForEach: "a,b",
+					},
+				},
+			},
+			isErr: true,
+		},
+		{
+			title: "matrix values are empty",
+			task: domain.Task{
+				Name: testValFoo,
+				Matrix: map[string][]string{
+					"goos": {},
+				},
+			},
+			isErr: true,
+		},
+		{
+			title: "matrix key duplicates with the flag",
+			task: domain.Task{
+				Name: testValFoo,
+				Flags: []domain.Flag{
+					{
+						Name: "goos",
+					},
+				},
+				Matrix: map[string][]string{
+					"goos": {"linux"},
+				},
+			},
+			isErr: true,
+		},
+		{
+			title: "steps",
+			task: domain.Task{
+				Name: testValFoo,
+				Steps: []domain.Step{
+					{
+						Script: testValPwd,
+					},
+				},
+			},
+		},
 		{
 			title: testTitleNormal,
 			task: domain.Task{
@@ -328,3 +512,35 @@ func Test_vTask(t *testing.T) {
 		})
 	}
 }
+
+func Test_duplicateSources(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title string
+		a     domain.Task
+		b     domain.Task
+		exp   string
+	}{
+		{
+			title: "no source",
+		},
+		{
+			title: "sources",
+			a:     domain.Task{Source: ".cmdx.yaml"},
+			b:     domain.Task{Source: ".cmdx.d/db.yaml"},
+			exp:   ": .cmdx.yaml, .cmdx.d/db.yaml",
+		},
+		{
+			title: "same source",
+			a:     domain.Task{Source: ".cmdx.yaml", Node: &yaml.Node{Line: 3}},
+			b:     domain.Task{Source: ".cmdx.yaml"},
+			exp:   ": the first task is defined at line 3",
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, d.exp, duplicateSources(d.a, d.b))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package variable
+
+import (
+	"text/template"
+	"text/template/parse"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// References returns names of variables referred by templates such as "version" of "{{.version}}".
+// Templates which can't be parsed are ignored, because the error is reported when they are rendered.
+func References(templates ...string) []string {
+	names := []string{}
+	seen := map[string]struct{}{}
+	add := func(name string) {
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	for _, s := range templates {
+		if s == "" {
+			continue
+		}
+		t, err := template.New("").Funcs(sprig.TxtFuncMap()).Parse(s)
+		if err != nil || t.Tree == nil {
+			continue
+		}
+		walk(t.Root, add)
+	}
+	return names
+}
+
+func walk(node parse.Node, add func(string)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			walk(c, add)
+		}
+	case *parse.ActionNode:
+		walk(n.Pipe, add)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Cmds {
+			walk(c, add)
+		}
+	case *parse.CommandNode:
+		for _, c := range n.Args {
+			walk(c, add)
+		}
+	case *parse.IfNode:
+		walkBranch(&n.BranchNode, add)
+	case *parse.RangeNode:
+		walkBranch(&n.BranchNode, add)
+	case *parse.WithNode:
+		walkBranch(&n.BranchNode, add)
+	case *parse.TemplateNode:
+		walk(n.Pipe, add)
+	case *parse.ChainNode:
+		walk(n.Node, add)
+	case *parse.FieldNode:
+		add(n.Ident[0])
+	case *parse.VariableNode:
+		// $.name
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			add(n.Ident[1])
+		}
+	}
+}
+
+func walkBranch(n *parse.BranchNode, add func(string)) {
+	walk(n.Pipe, add)
+	walk(n.List, add)
+	walk(n.ElseList, add)
+}
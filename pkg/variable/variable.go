@@ -0,0 +1,144 @@
+package variable
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+	"github.com/suzuki-shunsuke/cmdx/pkg/tmpl"
+)
+
+// Evaluator evaluates variables lazily.
+// Only variables referred by templates are evaluated, and each variable is evaluated at most once per run.
+type Evaluator struct {
+	mutex  sync.Mutex
+	global map[string]domain.Var
+	dir    string
+	cache  map[string]string
+}
+
+// New returns an evaluator of the configuration level variables.
+// dir is the working directory of commands of configuration level variables.
+func New(global map[string]domain.Var, dir string) *Evaluator {
+	return &Evaluator{
+		global: global,
+		dir:    dir,
+		cache:  map[string]string{},
+	}
+}
+
+// scope is a set of variables.
+// The configuration level variables are the parent scope of the task level variables.
+type scope struct {
+	name   string
+	vars   map[string]domain.Var
+	dir    string
+	parent *scope
+}
+
+func (s *scope) lookup(name string) (*scope, domain.Var, bool) {
+	for c := s; c != nil; c = c.parent {
+		if v, ok := c.vars[name]; ok {
+			return c, v, true
+		}
+	}
+	return nil, domain.Var{}, false
+}
+
+// Resolve evaluates variables referred by templates and returns their values.
+// task is the name of the task and local are the task level variables, which take precedence over the configuration level variables.
+// dir is the working directory of commands of the task level variables.
+func (e *Evaluator) Resolve(ctx context.Context, task string, local map[string]domain.Var, dir string, templates []string) (map[string]any, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	sc := &scope{
+		name: task,
+		vars: local,
+		dir:  dir,
+		parent: &scope{
+			vars: e.global,
+			dir:  e.dir,
+		},
+	}
+	vals := map[string]any{}
+	for _, name := range References(templates...) {
+		if _, ok := vals[name]; ok {
+			continue
+		}
+		s, v, ok := sc.lookup(name)
+		if !ok {
+			continue
+		}
+		val, err := e.evaluate(ctx, s, name, v, nil)
+		if err != nil {
+			return nil, err
+		}
+		vals[name] = val
+	}
+	return vals, nil
+}
+
+func (e *Evaluator) evaluate(ctx context.Context, sc *scope, name string, v domain.Var, stack []string) (string, error) {
+	key := sc.name + "\x00" + name
+	if val, ok := e.cache[key]; ok {
+		return val, nil
+	}
+	if i := slices.Index(stack, key); i != -1 {
+		names := make([]string, 0, len(stack)-i+1)
+		for _, k := range stack[i:] {
+			_, n, _ := strings.Cut(k, "\x00")
+			names = append(names, n)
+		}
+		return "", errors.New("the variable is circular: " + strings.Join(append(names, name), " -> "))
+	}
+	stack = append(stack, key)
+
+	var val string
+	if v.Sh != "" {
+		s, err := e.sh(ctx, v.Sh, sc.dir)
+		if err != nil {
+			return "", fmt.Errorf("failed to evaluate the variable %s: %w", name, err)
+		}
+		val = s
+	} else {
+		data := map[string]any{}
+		for _, ref := range References(v.Value) {
+			s, rv, ok := sc.lookup(ref)
+			if !ok {
+				continue
+			}
+			rval, err := e.evaluate(ctx, s, ref, rv, stack)
+			if err != nil {
+				return "", err
+			}
+			data[ref] = rval
+		}
+		s, err := tmpl.RenderTemplate(v.Value, data)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse the variable %s - %s: %w", name, v.Value, err)
+		}
+		val = s
+	}
+	e.cache[key] = val
+	return val, nil
+}
+
+// sh runs the command and returns its standard output without the trailing newlines.
+func (e *Evaluator) sh(ctx context.Context, command, dir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	buf := &bytes.Buffer{}
+	cmd.Stdout = buf
+	cmd.Stderr = os.Stderr
+	cmd.Dir = dir
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run the command - %s: %w", command, err)
+	}
+	return strings.TrimRight(buf.String(), "\r\n"), nil
+}
@@ -0,0 +1,127 @@
+package variable
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/cmdx/pkg/domain"
+)
+
+func TestReferences(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title     string
+		templates []string
+		exp       []string
+	}{
+		{
+			title:     "fields",
+			templates: []string{`echo {{.version}} {{if .sha}}{{.sha | upper}}{{end}}`, `{{$.version}} {{range .items}}{{.}}{{end}}`},
+			exp:       []string{"version", "sha", "items"},
+		},
+		{
+			title:     "invalid template",
+			templates: []string{"{{"},
+			exp:       []string{},
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, d.exp, References(d.templates...))
+		})
+	}
+}
+
+func TestEvaluator_Resolve(t *testing.T) {
+	t.Parallel()
+	data := []struct {
+		title     string
+		global    map[string]domain.Var
+		local     map[string]domain.Var
+		templates []string
+		exp       map[string]any
+		isErr     bool
+	}{
+		{
+			title: "literal, template, and command",
+			global: map[string]domain.Var{
+				"major":   {Value: "1"},
+				"minor":   {Sh: "echo 2"},
+				"version": {Value: "v{{.major}}.{{.minor}}"},
+				"unused":  {Sh: "exit 1"},
+			},
+			local: map[string]domain.Var{
+				"tag": {Value: "{{.version}}-rc"},
+			},
+			templates: []string{"echo {{.tag}} {{.version}} {{.name}}"},
+			exp: map[string]any{
+				"tag":     "v1.2-rc",
+				"version": "v1.2",
+			},
+		},
+		{
+			title: "task level variables take precedence",
+			global: map[string]domain.Var{
+				"version": {Value: "v1"},
+			},
+			local: map[string]domain.Var{
+				"version": {Value: "v2"},
+			},
+			templates: []string{"{{.version}}"},
+			exp: map[string]any{
+				"version": "v2",
+			},
+		},
+		{
+			title: "circular",
+			global: map[string]domain.Var{
+				"a": {Value: "{{.b}}"},
+				"b": {Value: "{{.a}}"},
+			},
+			templates: []string{"{{.a}}"},
+			isErr:     true,
+		},
+		{
+			title: "command fails",
+			global: map[string]domain.Var{
+				"sha": {Sh: "exit 1"},
+			},
+			templates: []string{"{{.sha}}"},
+			isErr:     true,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.title, func(t *testing.T) {
+			t.Parallel()
+			e := New(d.global, t.TempDir())
+			vals, err := e.Resolve(t.Context(), "build", d.local, t.TempDir(), d.templates)
+			if d.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, d.exp, vals)
+		})
+	}
+}
+
+func TestEvaluator_Resolve_once(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	e := New(map[string]domain.Var{
+		"sha": {Sh: "echo x >> log; echo abc"},
+	}, dir)
+	for _, task := range []string{"build", "test"} {
+		vals, err := e.Resolve(t.Context(), task, nil, dir, []string{"{{.sha}}"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"sha": "abc"}, vals)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "log"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(b), "x"))
+}